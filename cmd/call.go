@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gitea.com/gitea/gitea-mcp/operation"
+	"gitea.com/gitea/gitea-mcp/pkg/tool"
+	"gitea.com/gitea/gitea-mcp/pkg/tracing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	registerCommand("call", "Call a tool directly and print its result", runCall)
+}
+
+// argList collects repeated `--arg key=value` flags.
+type argList []string
+
+func (a *argList) String() string {
+	return strings.Join(*a, ",")
+}
+
+func (a *argList) Set(v string) error {
+	if !strings.Contains(v, "=") {
+		return fmt.Errorf("argument must be in key=value form: %s", v)
+	}
+	*a = append(*a, v)
+	return nil
+}
+
+func runCall(args []string) error {
+	var (
+		toolArgs argList
+		jsonArgs string
+	)
+	fs := flag.NewFlagSet("call", flag.ExitOnError)
+	fs.Var(&toolArgs, "arg", "tool argument in key=value form (repeatable)")
+	fs.StringVar(&jsonArgs, "json", "", "tool arguments as a JSON object")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gitea-mcp call <tool_name> [--arg key=value ...] [--json '{...}']\n")
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fs.Usage()
+		return fmt.Errorf("tool name is required")
+	}
+	name := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	t, ok := operation.GetTool(name)
+	if !ok {
+		if reason, hidden := tool.Hidden()[name]; hidden {
+			return fmt.Errorf("tool %s is not available: %s", name, reason)
+		}
+		return fmt.Errorf("tool %s not found", name)
+	}
+
+	arguments := map[string]any{}
+	if jsonArgs != "" {
+		if err := json.Unmarshal([]byte(jsonArgs), &arguments); err != nil {
+			return fmt.Errorf("parse --json err: %v", err)
+		}
+	}
+	for _, kv := range toolArgs {
+		key, raw, _ := strings.Cut(kv, "=")
+		v, err := parseToolArg(t.Tool, key, raw)
+		if err != nil {
+			return err
+		}
+		arguments[key] = v
+	}
+
+	ctx := context.Background()
+	shutdownTracing, err := tracing.Init(ctx)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(ctx)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = name
+	req.Params.Arguments = arguments
+	result, err := tracing.ToolMiddleware(operation.RequireSudo(t.Handler))(ctx, req)
+	if err != nil {
+		return err
+	}
+	printToolResult(result)
+	if result.IsError {
+		return fmt.Errorf("tool %s returned an error", name)
+	}
+	return nil
+}
+
+// parseToolArg converts a command-line value into the type the tool's input
+// schema declares, so handlers receive the same types as from an MCP client.
+func parseToolArg(t mcp.Tool, key, raw string) (any, error) {
+	prop, ok := t.InputSchema.Properties[key].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unknown argument %s for tool %s", key, t.Name)
+	}
+	typ, _ := prop["type"].(string)
+	switch typ {
+	case "number", "integer":
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s must be a number: %v", key, err)
+		}
+		return v, nil
+	case "boolean":
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("argument %s must be a boolean: %v", key, err)
+		}
+		return v, nil
+	case "array":
+		var v []any
+		if err := json.Unmarshal([]byte(raw), &v); err == nil {
+			return v, nil
+		}
+		for _, item := range strings.Split(raw, ",") {
+			v = append(v, strings.TrimSpace(item))
+		}
+		return v, nil
+	case "object":
+		var v map[string]any
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			return nil, fmt.Errorf("argument %s must be a JSON object: %v", key, err)
+		}
+		return v, nil
+	default:
+		return raw, nil
+	}
+}
+
+func printToolResult(result *mcp.CallToolResult) {
+	if result == nil {
+		return
+	}
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			fmt.Fprintln(os.Stdout, text.Text)
+			continue
+		}
+		b, err := json.Marshal(content)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "marshal content err: %v\n", err)
+			continue
+		}
+		fmt.Fprintln(os.Stdout, string(b))
+	}
+}
@@ -3,17 +3,23 @@ package cmd
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
 
 	"gitea.com/gitea/gitea-mcp/operation"
 	flagPkg "gitea.com/gitea/gitea-mcp/pkg/flag"
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
 	"gitea.com/gitea/gitea-mcp/pkg/log"
 )
 
 var (
-	host  string
-	port  int
-	token string
+	host      string
+	port      int
+	token     string
+	logRedact string
+	sudoAllow string
+	sudoMap   string
 )
 
 func init() {
@@ -65,6 +71,110 @@ func init() {
 		false,
 		"ignore TLS certificate errors",
 	)
+	flag.StringVar(
+		&flagPkg.CACert,
+		"ca-cert",
+		os.Getenv("GITEA_CA_CERT"),
+		"PEM file with additional CA certificates to trust",
+	)
+	flag.StringVar(
+		&flagPkg.ClientCert,
+		"client-cert",
+		os.Getenv("GITEA_CLIENT_CERT"),
+		"PEM client certificate for mutual TLS",
+	)
+	flag.StringVar(
+		&flagPkg.ClientKey,
+		"client-key",
+		os.Getenv("GITEA_CLIENT_KEY"),
+		"PEM private key of the client certificate",
+	)
+	flag.StringVar(
+		&flagPkg.Proxy,
+		"proxy",
+		os.Getenv("GITEA_PROXY"),
+		"proxy URL (http, https or socks5) for Gitea requests, or \"none\" to ignore HTTP(S)_PROXY (default from HTTP(S)_PROXY and NO_PROXY)",
+	)
+
+	flag.BoolVar(
+		&flagPkg.Sudo,
+		"sudo",
+		false,
+		"impersonate users with the Sudo header (requires an admin token and --sudo-allow)",
+	)
+	flag.StringVar(
+		&flagPkg.SudoUser,
+		"sudo-user",
+		os.Getenv("GITEA_SUDO_USER"),
+		"user to impersonate when a request carries no identity",
+	)
+	flag.StringVar(
+		&flagPkg.SudoHeader,
+		"sudo-header",
+		"X-Forwarded-User",
+		"HTTP header with the identity of the sse or http user to impersonate",
+	)
+	flag.StringVar(
+		&sudoAllow,
+		"sudo-allow",
+		os.Getenv("GITEA_SUDO_ALLOW"),
+		"comma separated users that may be impersonated",
+	)
+	flag.StringVar(
+		&sudoMap,
+		"sudo-map",
+		os.Getenv("GITEA_SUDO_MAP"),
+		"comma separated identity=user pairs mapping request identities to Gitea users",
+	)
+
+	flag.StringVar(
+		&flagPkg.LogPath,
+		"log-path",
+		os.Getenv("GITEA_LOG_PATH"),
+		"log file path (default $HOME/.gitea-mcp/gitea-mcp.log)",
+	)
+	flag.StringVar(
+		&flagPkg.LogLevel,
+		"log-level",
+		os.Getenv("GITEA_LOG_LEVEL"),
+		"log level (debug, info, warn or error)",
+	)
+	flag.StringVar(
+		&flagPkg.LogFormat,
+		"log-format",
+		os.Getenv("GITEA_LOG_FORMAT"),
+		"log encoding (console or json)",
+	)
+	flag.IntVar(
+		&flagPkg.LogMaxSize,
+		"log-max-size",
+		100,
+		"maximum log file size in megabytes before rotation",
+	)
+	flag.BoolVar(
+		&flagPkg.LogStderr,
+		"log-stderr",
+		false,
+		"also write logs to stderr in stdio mode",
+	)
+	flag.StringVar(
+		&logRedact,
+		"log-redact",
+		os.Getenv("GITEA_LOG_REDACT"),
+		"comma separated argument names whose values are redacted from logs",
+	)
+	flag.StringVar(
+		&flagPkg.OTLPEndpoint,
+		"otlp-endpoint",
+		"",
+		"OTLP/HTTP traces endpoint URL, e.g. http://localhost:4318/v1/traces (tracing is also enabled by OTEL_EXPORTER_OTLP_ENDPOINT)",
+	)
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: gitea-mcp [flags] [command]\n\nFlags:\n")
+		flag.PrintDefaults()
+		printCommands()
+	}
 
 	flag.Parse()
 
@@ -79,6 +189,7 @@ func init() {
 	if flagPkg.Token == "" {
 		flagPkg.Token = os.Getenv("GITEA_ACCESS_TOKEN")
 	}
+	loadStoredToken()
 
 	if os.Getenv("MCP_MODE") != "" {
 		flagPkg.Mode = os.Getenv("MCP_MODE")
@@ -96,10 +207,51 @@ func init() {
 	if os.Getenv("GITEA_INSECURE") == "true" {
 		flagPkg.Insecure = true
 	}
+
+	if os.Getenv("GITEA_LOG_STDERR") == "true" {
+		flagPkg.LogStderr = true
+	}
+
+	if os.Getenv("GITEA_SUDO") == "true" {
+		flagPkg.Sudo = true
+	}
+	if v := os.Getenv("GITEA_SUDO_HEADER"); v != "" {
+		flagPkg.SudoHeader = v
+	}
+	for _, user := range strings.Split(sudoAllow, ",") {
+		if user = strings.TrimSpace(user); user != "" {
+			flagPkg.SudoAllow = append(flagPkg.SudoAllow, user)
+		}
+	}
+	flagPkg.SudoMap = map[string]string{}
+	for _, pair := range strings.Split(sudoMap, ",") {
+		if identity, user, ok := strings.Cut(pair, "="); ok {
+			flagPkg.SudoMap[strings.TrimSpace(identity)] = strings.TrimSpace(user)
+		}
+	}
+
+	for _, name := range strings.Split(logRedact, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			flagPkg.LogRedactArgs = append(flagPkg.LogRedactArgs, name)
+		}
+	}
 }
 
 func Execute() {
+	for _, check := range []func() error{log.CheckFlags, gitea.CheckTransport, gitea.CheckSudo} {
+		if err := check(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
 	defer log.Default().Sync()
+	if flag.NArg() > 0 {
+		if err := runCommand(flag.Args()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	if err := operation.Run(); err != nil {
 		if err == context.Canceled {
 			log.Info("Server shutdown due to context cancellation")
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// command is a subcommand run instead of the MCP server, e.g. `gitea-mcp tools`.
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{}
+
+func registerCommand(name, usage string, run func(args []string) error) {
+	commands[name] = command{
+		usage: usage,
+		run:   run,
+	}
+}
+
+func runCommand(args []string) error {
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command: %s. Must be one of: %s", args[0], strings.Join(commandNames(), ", "))
+	}
+	return cmd.run(args[1:])
+}
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func printCommands() {
+	fmt.Fprintf(os.Stderr, "\nCommands:\n")
+	for _, name := range commandNames() {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].usage)
+	}
+}
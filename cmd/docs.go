@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"gitea.com/gitea/gitea-mcp/operation"
+)
+
+func init() {
+	registerCommand("docs", "Generate the tool reference from the registered tools", runDocs)
+}
+
+type toolDoc struct {
+	Name        string     `json:"name"`
+	Toolset     string     `json:"toolset"`
+	Description string     `json:"description"`
+	Write       bool       `json:"write"`
+	Scopes      []string   `json:"scopes"`
+	MinVersion  string     `json:"min_version,omitempty"`
+	Parameters  []paramDoc `json:"parameters"`
+}
+
+type paramDoc struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+	Default     any    `json:"default,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+}
+
+func runDocs(args []string) error {
+	var format, output string
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	fs.StringVar(&format, "format", "markdown", "output format (markdown or json)")
+	fs.StringVar(&output, "o", "", "write to file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	docs := collectToolDocs()
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("create %s err: %v", output, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch format {
+	case "markdown", "md":
+		return writeMarkdownDocs(w, docs)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(docs)
+	default:
+		return fmt.Errorf("invalid format: %s. Must be 'markdown' or 'json'", format)
+	}
+}
+
+// collectToolDocs walks every toolset, including write tools hidden by
+// read-only mode, so the reference always covers the whole server.
+func collectToolDocs() []toolDoc {
+	var docs []toolDoc
+	for _, ts := range operation.Toolsets() {
+		infos := ts.Tool.Infos()
+		sort.Slice(infos, func(i, j int) bool {
+			return infos[i].Tool.Name < infos[j].Tool.Name
+		})
+		for _, info := range infos {
+			doc := toolDoc{
+				Name:        info.Tool.Name,
+				Toolset:     ts.Name,
+				Description: info.Tool.Description,
+				Write:       info.Write,
+				Scopes:      info.Scopes,
+				MinVersion:  info.MinVersion,
+				Parameters:  []paramDoc{},
+			}
+			if doc.Scopes == nil {
+				doc.Scopes = []string{}
+			}
+			names := make([]string, 0, len(info.Tool.InputSchema.Properties))
+			for name := range info.Tool.InputSchema.Properties {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				prop, _ := info.Tool.InputSchema.Properties[name].(map[string]any)
+				p := paramDoc{
+					Name:     name,
+					Required: slices.Contains(info.Tool.InputSchema.Required, name),
+					Default:  prop["default"],
+				}
+				p.Type = propertyType(prop)
+				p.Description, _ = prop["description"].(string)
+				switch enum := prop["enum"].(type) {
+				case []string:
+					for _, v := range enum {
+						p.Enum = append(p.Enum, v)
+					}
+				case []any:
+					p.Enum = enum
+				}
+				doc.Parameters = append(doc.Parameters, p)
+			}
+			docs = append(docs, doc)
+		}
+	}
+	return docs
+}
+
+// propertyType returns the JSON schema type of a tool parameter, joining the
+// alternatives of a oneOf with "or".
+func propertyType(prop map[string]any) string {
+	if typ, ok := prop["type"].(string); ok {
+		return typ
+	}
+	alternatives, _ := prop["oneOf"].([]any)
+	types := make([]string, 0, len(alternatives))
+	for _, alt := range alternatives {
+		if schema, ok := alt.(map[string]any); ok {
+			if typ, ok := schema["type"].(string); ok {
+				types = append(types, typ)
+			}
+		}
+	}
+	return strings.Join(types, " or ")
+}
+
+func writeMarkdownDocs(w io.Writer, docs []toolDoc) error {
+	var b strings.Builder
+	b.WriteString("# Gitea MCP Server Tools\n\n")
+	b.WriteString("<!-- Code generated by `gitea-mcp docs`. DO NOT EDIT. -->\n")
+
+	toolset := ""
+	for _, doc := range docs {
+		if doc.Toolset != toolset {
+			toolset = doc.Toolset
+			fmt.Fprintf(&b, "\n## %s\n", toolset)
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", doc.Name)
+		if doc.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", doc.Description)
+		}
+		access := "read"
+		if doc.Write {
+			access = "write (disabled in read-only mode)"
+		}
+		fmt.Fprintf(&b, "- Access: %s\n", access)
+		scopes := "none"
+		if len(doc.Scopes) > 0 {
+			scopes = "`" + strings.Join(doc.Scopes, "`, `") + "`"
+		}
+		fmt.Fprintf(&b, "- Token scopes: %s\n", scopes)
+		if doc.MinVersion != "" {
+			fmt.Fprintf(&b, "- Requires: Gitea >= %s\n", doc.MinVersion)
+		}
+
+		if len(doc.Parameters) == 0 {
+			continue
+		}
+		b.WriteString("\n| Parameter | Type | Required | Default | Description |\n")
+		b.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, p := range doc.Parameters {
+			required := ""
+			if p.Required {
+				required = "yes"
+			}
+			def := ""
+			if p.Default != nil {
+				def = fmt.Sprintf("`%v`", p.Default)
+			}
+			desc := p.Description
+			if len(p.Enum) > 0 {
+				values := make([]string, 0, len(p.Enum))
+				for _, v := range p.Enum {
+					values = append(values, fmt.Sprintf("`%v`", v))
+				}
+				desc += " One of: " + strings.Join(values, ", ") + "."
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				p.Name, p.Type, required, def, escapeMarkdownCell(desc))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"gitea.com/gitea/gitea-mcp/operation/issue"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+	"gitea.com/gitea/gitea-mcp/pkg/tracing"
+)
+
+func init() {
+	registerCommand("export", "Export the issues and pull requests of a repository to a directory", runExport)
+}
+
+const archiveFile = "archive.json"
+
+func runExport(args []string) error {
+	var (
+		owner, repo, output, since string
+		limit                      int
+		markdown, noTimeline, full bool
+	)
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.StringVar(&owner, "owner", "", "repository owner")
+	fs.StringVar(&repo, "repo", "", "repository name")
+	fs.StringVar(&output, "o", "", "directory to write the archive to (default owner-repo)")
+	fs.StringVar(&since, "since", "", "only export issues updated since this date, RFC3339 or YYYY-MM-DD (default where the previous export to the directory stopped)")
+	fs.IntVar(&limit, "limit", 0, "export at most this many issues, oldest update first; run again to continue (default all)")
+	fs.BoolVar(&markdown, "markdown", false, "also write a markdown file per issue")
+	fs.BoolVar(&noTimeline, "no-timeline", false, "leave out issue timelines")
+	fs.BoolVar(&full, "full", false, "export everything again instead of resuming the previous export")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gitea-mcp export --owner <owner> --repo <repo> [-o dir] [--since date] [--limit n] [--markdown]\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if owner == "" || repo == "" {
+		fs.Usage()
+		return fmt.Errorf("owner and repo are required")
+	}
+	if output == "" {
+		output = owner + "-" + repo
+	}
+
+	// resume from the archive of a previous export to the directory
+	var previous *issue.Archive
+	if !full {
+		var err error
+		previous, err = readArchive(filepath.Join(output, archiveFile))
+		if err != nil {
+			return err
+		}
+		if previous != nil && (previous.Owner != owner || previous.Repo != repo) {
+			return fmt.Errorf("%s holds an export of %s/%s, not %s/%s", output, previous.Owner, previous.Repo, owner, repo)
+		}
+	}
+
+	opt := issue.ExportOptions{Limit: limit, Timeline: !noTimeline, Markdown: markdown}
+	switch {
+	case since != "":
+		t, err := to.Time(since)
+		if err != nil {
+			return fmt.Errorf("since: %v", err)
+		}
+		opt.Since = t
+	case previous != nil && previous.Cursor != "":
+		opt.Cursor = previous.Cursor
+	case previous != nil && previous.Since != nil:
+		opt.Since = *previous.Since
+	}
+
+	ctx := context.Background()
+	shutdownTracing, err := tracing.Init(ctx)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(ctx)
+
+	archive, err := issue.Export(ctx, owner, repo, opt)
+	if err != nil {
+		return err
+	}
+	updated := archive.Issues
+	if previous != nil {
+		previous.Merge(archive)
+		archive = previous
+	}
+
+	if err := os.MkdirAll(output, 0o755); err != nil {
+		return fmt.Errorf("create %s err: %v", output, err)
+	}
+	if markdown {
+		dir := filepath.Join(output, "issues")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create %s err: %v", dir, err)
+		}
+		for _, e := range updated {
+			name := filepath.Join(dir, strconv.FormatInt(e.Issue.Index, 10)+".md")
+			if err := os.WriteFile(name, []byte(e.Markdown), 0o644); err != nil {
+				return fmt.Errorf("write %s err: %v", name, err)
+			}
+		}
+	}
+	if err := writeArchive(filepath.Join(output, archiveFile), archive); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d updated issues of %s/%s to %s (%d in archive)\n", len(updated), owner, repo, output, len(archive.Issues))
+	if archive.Truncated {
+		fmt.Fprintf(os.Stderr, "More issues remain to export, run export again to continue\n")
+	}
+	return nil
+}
+
+// readArchive reads the archive of a previous export, or returns nil when
+// there is none.
+func readArchive(name string) (*issue.Archive, error) {
+	b, err := os.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read %s err: %v", name, err)
+	}
+	var archive issue.Archive
+	if err := json.Unmarshal(b, &archive); err != nil {
+		return nil, fmt.Errorf("parse %s err: %v", name, err)
+	}
+	if archive.Version != issue.ArchiveVersion {
+		return nil, fmt.Errorf("%s has archive version %d, this version of gitea-mcp writes %d; export with --full to start over", name, archive.Version, issue.ArchiveVersion)
+	}
+	return &archive, nil
+}
+
+// writeArchive writes the archive through a temporary file, so an
+// interrupted export leaves the previous archive intact.
+func writeArchive(name string, archive *issue.Archive) error {
+	b, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal archive err: %v", err)
+	}
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return fmt.Errorf("write %s err: %v", tmp, err)
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		return fmt.Errorf("rename %s err: %v", tmp, err)
+	}
+	return nil
+}
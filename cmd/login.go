@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gitea.com/gitea/gitea-mcp/pkg/credential"
+	flagPkg "gitea.com/gitea/gitea-mcp/pkg/flag"
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/tool"
+
+	sdk "code.gitea.io/sdk/gitea"
+	"golang.org/x/term"
+)
+
+func init() {
+	registerCommand("login", "Create an access token with your username and password and store it", runLogin)
+	registerCommand("logout", "Remove a token stored by login", runLogout)
+	registerCommand("whoami", "Show the user and scopes of the configured token", runWhoami)
+}
+
+// tokenSource describes where the configured token came from, for whoami.
+var tokenSource string
+
+// loadStoredToken falls back to the token stored by login when none was
+// given by flag or environment. Without a configured host the host of the
+// last login is used.
+func loadStoredToken() {
+	if flagPkg.Token != "" {
+		return
+	}
+	cred, err := credential.Get(host)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	if cred == nil {
+		return
+	}
+	if host == "" {
+		flagPkg.Host = cred.Host
+	}
+	flagPkg.Token = cred.Token
+	tokenSource = "credentials file " + credential.Path()
+}
+
+var stdin = bufio.NewReader(os.Stdin)
+
+func prompt(label string) (string, error) {
+	fmt.Fprint(os.Stderr, label)
+	line, err := stdin.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read %s err: %v", strings.TrimSuffix(label, ": "), err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// promptSecret reads a value without echoing it when stdin is a terminal.
+func promptSecret(label string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return prompt(label)
+	}
+	fmt.Fprint(os.Stderr, label)
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("read %s err: %v", strings.TrimSuffix(label, ": "), err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// defaultScopes returns the token scopes needed by every registered tool,
+// or only their read scopes in read-only mode.
+func defaultScopes() []string {
+	set := map[string]bool{}
+	for _, info := range tool.All() {
+		if info.Write && flagPkg.ReadOnly {
+			continue
+		}
+		for _, scope := range info.Scopes {
+			set[scope] = true
+		}
+	}
+	var scopes []string
+	for scope := range set {
+		// write implies read
+		if category, ok := strings.CutPrefix(scope, "read:"); ok && set[tool.WriteScope(category)] {
+			continue
+		}
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
+
+func runLogin(args []string) error {
+	var loginHost, user, tokenName, scopes, existing string
+	fs := flag.NewFlagSet("login", flag.ExitOnError)
+	fs.StringVar(&loginHost, "host", flagPkg.Host, "Gitea host")
+	fs.StringVar(&user, "user", "", "username (prompted when empty)")
+	fs.StringVar(&tokenName, "token-name", "", "name of the created token (default gitea-mcp-<timestamp>)")
+	fs.StringVar(&scopes, "scopes", strings.Join(defaultScopes(), ","), "comma separated scopes of the created token")
+	fs.StringVar(&existing, "with-token", "", "store this existing token instead of creating one, - reads it from stdin")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	flagPkg.Host = credential.NormalizeHost(loginHost)
+
+	cred := credential.Credential{
+		Host:      flagPkg.Host,
+		CreatedAt: time.Now().UTC(),
+	}
+	if existing != "" {
+		if existing == "-" {
+			var err error
+			if existing, err = promptSecret("Token: "); err != nil {
+				return err
+			}
+		}
+		cred.Token = existing
+	} else {
+		var err error
+		if cred, err = createToken(cred, user, tokenName, scopes); err != nil {
+			return err
+		}
+	}
+
+	// verify the token before storing it
+	ctx := gitea.WithToken(context.Background(), cred.Token)
+	me, _, err := gitea.ClientFromContext(ctx).GetMyUserInfo()
+	if err != nil {
+		return fmt.Errorf("verify token err: %v", err)
+	}
+	cred.User = me.UserName
+	if err := credential.Save(cred); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Logged in to %s as %s. Token stored in %s\n", cred.Host, cred.User, credential.Path())
+	return nil
+}
+
+// createToken mints a scoped access token with basic auth, asking for a
+// one-time password when the account has two-factor authentication enabled.
+func createToken(cred credential.Credential, user, tokenName, scopes string) (credential.Credential, error) {
+	var err error
+	if user == "" {
+		if user, err = prompt("Username: "); err != nil {
+			return cred, err
+		}
+	}
+	password, err := promptSecret("Password: ")
+	if err != nil {
+		return cred, err
+	}
+	if tokenName == "" {
+		tokenName = fmt.Sprintf("gitea-mcp-%d", cred.CreatedAt.Unix())
+	}
+	opt := sdk.CreateAccessTokenOption{Name: tokenName}
+	for _, scope := range strings.Split(scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			opt.Scopes = append(opt.Scopes, sdk.AccessTokenScope(scope))
+			cred.Scopes = append(cred.Scopes, scope)
+		}
+	}
+
+	otp := ""
+	for {
+		client, err := gitea.BasicAuthClient(user, password, otp)
+		if err != nil {
+			return cred, fmt.Errorf("create gitea client err: %v", err)
+		}
+		token, _, err := client.CreateAccessToken(opt)
+		if err != nil {
+			if otp == "" && strings.Contains(strings.ToLower(err.Error()), "otp") {
+				if otp, err = prompt("One-time password: "); err != nil {
+					return cred, err
+				}
+				if otp != "" {
+					continue
+				}
+				return cred, errors.New("one-time password is required")
+			}
+			return cred, fmt.Errorf("create access token err: %v", err)
+		}
+		cred.Token = token.Token
+		cred.TokenName = token.Name
+		return cred, nil
+	}
+}
+
+func runLogout(args []string) error {
+	var logoutHost string
+	fs := flag.NewFlagSet("logout", flag.ExitOnError)
+	fs.StringVar(&logoutHost, "host", host, "Gitea host (default the host of the last login)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cred, err := credential.Delete(logoutHost)
+	if err != nil {
+		return err
+	}
+	if cred == nil {
+		return errors.New("not logged in")
+	}
+	fmt.Fprintf(os.Stderr, "Removed the token for %s from %s\n", cred.Host, credential.Path())
+	if cred.TokenName != "" {
+		fmt.Fprintf(os.Stderr, "The token %q stays valid until you delete it in %s/user/settings/applications\n", cred.TokenName, cred.Host)
+	}
+	return nil
+}
+
+func runWhoami(args []string) error {
+	fs := flag.NewFlagSet("whoami", flag.ExitOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if flagPkg.Token == "" {
+		return errors.New("no token configured. Run `gitea-mcp login` or pass --token")
+	}
+	me, _, err := gitea.ClientFromContext(context.Background()).GetMyUserInfo()
+	if err != nil {
+		return fmt.Errorf("get user info err: %v", err)
+	}
+	source := tokenSource
+	if source == "" {
+		source = "--token flag or GITEA_ACCESS_TOKEN"
+	}
+	fmt.Printf("Host:   %s\n", flagPkg.Host)
+	fmt.Printf("User:   %s\n", me.UserName)
+	fmt.Printf("Token:  %s\n", source)
+	if scopes, err := gitea.TokenScopes(flagPkg.Token); err != nil {
+		fmt.Printf("Scopes: unknown (%v)\n", err)
+	} else {
+		fmt.Printf("Scopes: %s\n", strings.Join(scopes.List(), ", "))
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"gitea.com/gitea/gitea-mcp/operation"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func init() {
+	registerCommand("tools", "List registered tools with their schemas", runTools)
+}
+
+func runTools(args []string) error {
+	var asJSON bool
+	fs := flag.NewFlagSet("tools", flag.ExitOnError)
+	fs.BoolVar(&asJSON, "json", false, "print the full tool definitions as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	tools := make([]mcp.Tool, 0)
+	for _, t := range operation.Tools() {
+		tools = append(tools, t.Tool)
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tools)
+	}
+
+	for _, t := range tools {
+		fmt.Printf("%s\n    %s\n", t.Name, t.Description)
+		names := make([]string, 0, len(t.InputSchema.Properties))
+		for name := range t.InputSchema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, _ := t.InputSchema.Properties[name].(map[string]any)
+			typ := propertyType(prop)
+			desc, _ := prop["description"].(string)
+			if slices.Contains(t.InputSchema.Required, name) {
+				typ += ", required"
+			}
+			fmt.Printf("    --arg %s (%s): %s\n", name, typ, desc)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package issue
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	ListIssueAttachmentsToolName    = "list_issue_attachments"
+	DownloadIssueAttachmentToolName = "download_issue_attachment"
+	UploadIssueAttachmentToolName   = "upload_issue_attachment"
+)
+
+const (
+	// issue and comment attachments have an API since Gitea 1.17
+	attachmentMinVersion = "1.17.0"
+
+	defaultAttachmentSize = 10 << 20
+	maxAttachmentSize     = 50 << 20
+)
+
+var (
+	ListIssueAttachmentsTool = mcp.NewTool(
+		ListIssueAttachmentsToolName,
+		mcp.WithDescription("List the attachments of an issue, or of one of its comments"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Description("repository issue index, required unless commentID is set")),
+		mcp.WithNumber("commentID", mcp.Description("id of the comment whose attachments to list")),
+	)
+
+	DownloadIssueAttachmentTool = mcp.NewTool(
+		DownloadIssueAttachmentToolName,
+		mcp.WithDescription("Download an attachment of an issue or comment, as text, as a base64 blob or as image content"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Description("repository issue index, required unless commentID is set")),
+		mcp.WithNumber("commentID", mcp.Description("id of the comment the attachment belongs to")),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("attachment id")),
+		mcp.WithString("format", mcp.Description("auto returns images as image content, UTF-8 text as text and anything else as a base64 blob"), mcp.Enum("auto", "text", "base64", "image"), mcp.DefaultString("auto")),
+		mcp.WithNumber("maxSize", mcp.Description(fmt.Sprintf("largest attachment to download in bytes, at most %d", maxAttachmentSize)), mcp.DefaultNumber(defaultAttachmentSize)),
+	)
+
+	UploadIssueAttachmentTool = mcp.NewTool(
+		UploadIssueAttachmentToolName,
+		mcp.WithDescription("Upload an attachment to an issue or comment"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Description("repository issue index, required unless commentID is set")),
+		mcp.WithNumber("commentID", mcp.Description("id of the comment to attach to")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("file name, whose extension also helps detect the MIME type")),
+		mcp.WithString("content", mcp.Required(), mcp.Description("file content")),
+		mcp.WithString("encoding", mcp.Description("encoding of content"), mcp.Enum("text", "base64"), mcp.DefaultString("text")),
+	)
+)
+
+func init() {
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListIssueAttachmentsTool,
+		Handler: ListIssueAttachmentsFn,
+	})
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    DownloadIssueAttachmentTool,
+		Handler: DownloadIssueAttachmentFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    UploadIssueAttachmentTool,
+		Handler: UploadIssueAttachmentFn,
+	})
+	for _, name := range []string{
+		ListIssueAttachmentsToolName,
+		DownloadIssueAttachmentToolName,
+		UploadIssueAttachmentToolName,
+	} {
+		Tool.RequireVersion(name, attachmentMinVersion)
+	}
+}
+
+// assetsPath returns the attachments endpoint of the issue or comment named
+// by the index and commentID arguments, and a description for errors.
+func assetsPath(req mcp.CallToolRequest) (string, string, error) {
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return "", "", fmt.Errorf("owner is required")
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return "", "", fmt.Errorf("repo is required")
+	}
+	base := fmt.Sprintf("/repos/%s/%s/issues", url.PathEscape(owner), url.PathEscape(repo))
+	if commentID, ok := req.GetArguments()["commentID"].(float64); ok {
+		return fmt.Sprintf("%s/comments/%d/assets", base, int64(commentID)),
+			fmt.Sprintf("%v/%v/issues/comments/%v", owner, repo, int64(commentID)), nil
+	}
+	index, ok := req.GetArguments()["index"].(float64)
+	if !ok {
+		return "", "", fmt.Errorf("index or commentID is required")
+	}
+	return fmt.Sprintf("%s/%d/assets", base, int64(index)),
+		fmt.Sprintf("%v/%v/issues/%v", owner, repo, int64(index)), nil
+}
+
+// detectMIME sniffs the content type of data, falling back to the type of
+// the file name's extension when sniffing finds nothing specific.
+func detectMIME(name string, data []byte) string {
+	sniffed := http.DetectContentType(data)
+	if sniffed != "application/octet-stream" && !strings.HasPrefix(sniffed, "text/plain") {
+		return sniffed
+	}
+	if byExt := mime.TypeByExtension(path.Ext(name)); byExt != "" {
+		return byExt
+	}
+	return sniffed
+}
+
+func isText(mimeType string, data []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(mimeType)
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		mediaType == "application/json",
+		mediaType == "application/xml",
+		mediaType == "application/x-yaml",
+		mediaType == "application/yaml":
+		return utf8.Valid(data)
+	}
+	return mediaType == "application/octet-stream" && utf8.Valid(data)
+}
+
+func ListIssueAttachmentsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListIssueAttachmentsFn")
+	assets, target, err := assetsPath(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	var attachments []*gitea_sdk.Attachment
+	if err := gitea.Do(ctx, "GET", assets, nil, &attachments); err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v attachments err: %v", target, err))
+	}
+	return to.TextResult(attachments)
+}
+
+// DownloadedAttachment describes the content returned by
+// download_issue_attachment.
+type DownloadedAttachment struct {
+	*gitea_sdk.Attachment
+	MIMEType string `json:"mime_type"`
+	Content  string `json:"content,omitempty"`
+}
+
+func DownloadIssueAttachmentFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called DownloadIssueAttachmentFn")
+	assets, target, err := assetsPath(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	id, ok := req.GetArguments()["id"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("id is required"))
+	}
+	format, ok := req.GetArguments()["format"].(string)
+	if !ok {
+		format = "auto"
+	}
+	maxSize, ok := req.GetArguments()["maxSize"].(float64)
+	if !ok {
+		maxSize = defaultAttachmentSize
+	}
+	if maxSize <= 0 || maxSize > maxAttachmentSize {
+		return to.ErrorResult(fmt.Errorf("maxSize must be between 1 and %d", maxAttachmentSize))
+	}
+
+	attachment := &gitea_sdk.Attachment{}
+	if err := gitea.Do(ctx, "GET", fmt.Sprintf("%s/%d", assets, int64(id)), nil, attachment); err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v attachment %v err: %v", target, int64(id), err))
+	}
+	if attachment.Size > int64(maxSize) {
+		return to.ErrorResult(fmt.Errorf("attachment %s is %d bytes, more than maxSize %d", attachment.Name, attachment.Size, int64(maxSize)))
+	}
+	data, err := gitea.Download(ctx, attachment.DownloadURL, int64(maxSize))
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("download attachment %s err: %v", attachment.Name, err))
+	}
+
+	result := DownloadedAttachment{
+		Attachment: attachment,
+		MIMEType:   detectMIME(attachment.Name, data),
+	}
+	if format == "auto" {
+		switch {
+		case strings.HasPrefix(result.MIMEType, "image/"):
+			format = "image"
+		case isText(result.MIMEType, data):
+			format = "text"
+		default:
+			format = "base64"
+		}
+	}
+	switch format {
+	case "text":
+		if !utf8.Valid(data) {
+			return to.ErrorResult(fmt.Errorf("attachment %s is %s, not UTF-8 text", attachment.Name, result.MIMEType))
+		}
+		result.Content = string(data)
+		return to.TextResult(result)
+	case "image":
+		if !strings.HasPrefix(result.MIMEType, "image/") {
+			return to.ErrorResult(fmt.Errorf("attachment %s is %s, not an image", attachment.Name, result.MIMEType))
+		}
+		meta, err := json.Marshal(result)
+		if err != nil {
+			return to.ErrorResult(fmt.Errorf("marshal result err: %v", err))
+		}
+		return mcp.NewToolResultImage(string(meta), base64.StdEncoding.EncodeToString(data), result.MIMEType), nil
+	case "base64":
+		meta, err := json.Marshal(result)
+		if err != nil {
+			return to.ErrorResult(fmt.Errorf("marshal result err: %v", err))
+		}
+		return mcp.NewToolResultResource(string(meta), mcp.BlobResourceContents{
+			URI:      attachment.DownloadURL,
+			MIMEType: result.MIMEType,
+			Blob:     base64.StdEncoding.EncodeToString(data),
+		}), nil
+	default:
+		return to.ErrorResult(fmt.Errorf("invalid format: %s", format))
+	}
+}
+
+func UploadIssueAttachmentFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called UploadIssueAttachmentFn")
+	assets, target, err := assetsPath(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	name, ok := req.GetArguments()["name"].(string)
+	if !ok || name == "" {
+		return to.ErrorResult(fmt.Errorf("name is required"))
+	}
+	content, ok := req.GetArguments()["content"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("content is required"))
+	}
+	data := []byte(content)
+	if encoding, _ := req.GetArguments()["encoding"].(string); encoding == "base64" {
+		if data, err = base64.StdEncoding.DecodeString(content); err != nil {
+			return to.ErrorResult(fmt.Errorf("decode base64 content err: %v", err))
+		}
+	}
+	if len(data) > maxAttachmentSize {
+		return to.ErrorResult(fmt.Errorf("attachment is %d bytes, more than the limit of %d", len(data), maxAttachmentSize))
+	}
+
+	attachment := &gitea_sdk.Attachment{}
+	query := url.Values{"name": {name}}
+	if err := gitea.Upload(ctx, assets+"?"+query.Encode(), "attachment", name, detectMIME(name, data), data, attachment); err != nil {
+		return to.ErrorResult(fmt.Errorf("upload %v attachment err: %v", target, err))
+	}
+	return to.TextResult(attachment)
+}
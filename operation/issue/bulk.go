@@ -0,0 +1,408 @@
+package issue
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/ptr"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+	"gitea.com/gitea/gitea-mcp/pkg/tool"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	BulkEditIssuesToolName = "bulk_edit_issues"
+)
+
+const (
+	defaultBulkLimit       = 50
+	maxBulkLimit           = 500
+	defaultBulkConcurrency = 4
+	maxBulkConcurrency     = 10
+)
+
+var BulkEditIssuesTool = mcp.NewTool(
+	BulkEditIssuesToolName,
+	mcp.WithDescription("Apply the same changes to many issues, selected by index or by the filters of list_repo_issues: close or reopen, add or remove labels, set the milestone, add or remove assignees and comment. Reports the outcome per issue; use dryRun to preview"),
+	mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+	mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+	mcp.WithArray("indexes", mcp.Description("issue indexes to change; when empty, the filters select the issues"), mcp.Items(map[string]interface{}{"type": "number"})),
+	mcp.WithString("state", mcp.Description("filter: issue state"), mcp.Enum("open", "closed", "all"), mcp.DefaultString("open")),
+	mcp.WithString("type", mcp.Description("filter: whether to select issues, pull requests or both"), mcp.Enum("issues", "pulls", "all"), mcp.DefaultString("issues")),
+	mcp.WithArray("labels", mcp.Description("filter: only issues with all of these label names"), mcp.Items(map[string]interface{}{"type": "string"})),
+	mcp.WithArray("milestones", mcp.Description("filter: only issues in one of these milestones, by title or ID"), mcp.Items(map[string]interface{}{"type": "string"})),
+	mcp.WithString("q", mcp.Description("filter: search keyword in title and body")),
+	mcp.WithString("assignee", mcp.Description("filter: only issues assigned to this username")),
+	mcp.WithString("created_by", mcp.Description("filter: only issues created by this username")),
+	mcp.WithString("mentioned_by", mcp.Description("filter: only issues mentioning this username")),
+	mcp.WithString("since", mcp.Description("filter: only issues updated at or after this time, YYYY-MM-DD or RFC 3339")),
+	mcp.WithString("before", mcp.Description("filter: only issues updated at or before this time, YYYY-MM-DD or RFC 3339")),
+	mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("maximum number of issues the filters select, at most %d", maxBulkLimit)), mcp.DefaultNumber(defaultBulkLimit)),
+	mcp.WithString("set_state", mcp.Description("action: close or reopen the issues"), mcp.Enum("open", "closed")),
+	mcp.WithArray("add_labels", mcp.Description("action: label names to add"), mcp.Items(map[string]interface{}{"type": "string"})),
+	mcp.WithArray("remove_labels", mcp.Description("action: label names to remove"), mcp.Items(map[string]interface{}{"type": "string"})),
+	mcp.WithNumber("set_milestone", tool.NumberOrString(), mcp.Description("action: milestone ID or title, 0 removes the milestone")),
+	mcp.WithArray("add_assignees", mcp.Description("action: usernames to assign"), mcp.Items(map[string]interface{}{"type": "string"})),
+	mcp.WithArray("remove_assignees", mcp.Description("action: usernames to unassign"), mcp.Items(map[string]interface{}{"type": "string"})),
+	mcp.WithString("comment", mcp.Description("action: comment to post on each issue")),
+	mcp.WithBoolean("dryRun", mcp.Description("only report the issues selected and the changes that would be made"), mcp.DefaultBool(false)),
+	mcp.WithNumber("concurrency", mcp.Description(fmt.Sprintf("number of issues changed in parallel, at most %d", maxBulkConcurrency)), mcp.DefaultNumber(defaultBulkConcurrency)),
+)
+
+func init() {
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    BulkEditIssuesTool,
+		Handler: BulkEditIssuesFn,
+	})
+}
+
+// bulkActions are the changes of a bulk edit, resolved once for all issues.
+type bulkActions struct {
+	state           string
+	addLabels       []bulkLabel
+	removeLabels    []bulkLabel
+	setMilestone    bool
+	milestone       int64
+	milestoneTitle  string
+	addAssignees    []string
+	removeAssignees []string
+	comment         string
+}
+
+// BulkItem reports what a bulk edit did, or would do, to an issue.
+type BulkItem struct {
+	Index   int64    `json:"index"`
+	Title   string   `json:"title,omitempty"`
+	Changes []string `json:"changes"`
+	// Status is planned in dry runs, otherwise unchanged, succeeded or failed.
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkReport is the result of bulk_edit_issues.
+type BulkReport struct {
+	DryRun    bool        `json:"dry_run"`
+	Selected  int         `json:"selected"`
+	Succeeded int         `json:"succeeded"`
+	Failed    int         `json:"failed"`
+	Unchanged int         `json:"unchanged"`
+	Items     []*BulkItem `json:"items"`
+}
+
+type bulkLabel struct {
+	id   int64
+	name string
+}
+
+func bulkLabels(ctx context.Context, owner, repo string, names []string) ([]bulkLabel, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	ids, err := resolveLabelIDs(ctx, owner, repo, names)
+	if err != nil {
+		return nil, err
+	}
+	labels := make([]bulkLabel, len(ids))
+	for i, id := range ids {
+		labels[i] = bulkLabel{id: id, name: names[i]}
+	}
+	return labels, nil
+}
+
+func bulkActionsFromRequest(ctx context.Context, req mcp.CallToolRequest, owner, repo string) (*bulkActions, error) {
+	a := &bulkActions{
+		addAssignees:    to.Strings(req.GetArguments()["add_assignees"]),
+		removeAssignees: to.Strings(req.GetArguments()["remove_assignees"]),
+	}
+	a.state, _ = req.GetArguments()["set_state"].(string)
+	if a.state != "" && a.state != "open" && a.state != "closed" {
+		return nil, fmt.Errorf("set_state must be open or closed")
+	}
+	a.comment, _ = req.GetArguments()["comment"].(string)
+	var err error
+	if a.addLabels, err = bulkLabels(ctx, owner, repo, to.Strings(req.GetArguments()["add_labels"])); err != nil {
+		return nil, err
+	}
+	if a.removeLabels, err = bulkLabels(ctx, owner, repo, to.Strings(req.GetArguments()["remove_labels"])); err != nil {
+		return nil, err
+	}
+	if milestone := req.GetArguments()["set_milestone"]; gitea.MilestoneGiven(milestone) {
+		a.setMilestone = true
+		if a.milestone, err = gitea.ResolveMilestoneID(ctx, owner, repo, milestone); err != nil {
+			return nil, err
+		}
+		a.milestoneTitle = fmt.Sprint(milestone)
+	}
+	if a.state == "" && len(a.addLabels) == 0 && len(a.removeLabels) == 0 && !a.setMilestone &&
+		len(a.addAssignees) == 0 && len(a.removeAssignees) == 0 && a.comment == "" {
+		return nil, fmt.Errorf("no action given")
+	}
+	return a, nil
+}
+
+// selectIssues returns the issues named by indexes, or else the issues the
+// filters match, up to limit. An index that can't be fetched is returned as
+// an issue with only its index, and fails in the report.
+func selectIssues(ctx context.Context, req mcp.CallToolRequest, owner, repo string) ([]*gitea_sdk.Issue, map[int64]error, error) {
+	client := gitea.ClientFromContext(ctx)
+	if indexes, ok := req.GetArguments()["indexes"].([]any); ok && len(indexes) > 0 {
+		issues := make([]*gitea_sdk.Issue, 0, len(indexes))
+		errs := map[int64]error{}
+		seen := map[float64]bool{}
+		for _, v := range indexes {
+			index, ok := v.(float64)
+			if !ok {
+				return nil, nil, fmt.Errorf("indexes must be numbers")
+			}
+			// an issue given twice would be changed twice in parallel
+			if seen[index] {
+				continue
+			}
+			seen[index] = true
+			issue, _, err := client.GetIssue(owner, repo, int64(index))
+			if err != nil {
+				issue = &gitea_sdk.Issue{Index: int64(index)}
+				errs[issue.Index] = fmt.Errorf("get issue err: %v", err)
+			}
+			issues = append(issues, issue)
+		}
+		return issues, errs, nil
+	}
+
+	limit, ok := req.GetArguments()["limit"].(float64)
+	if !ok {
+		limit = defaultBulkLimit
+	}
+	if limit < 1 || limit > maxBulkLimit {
+		return nil, nil, fmt.Errorf("limit must be between 1 and %d", maxBulkLimit)
+	}
+	opt, err := listIssueOptions(req, "open", "issues")
+	if err != nil {
+		return nil, nil, err
+	}
+	const pageSize = 50
+	var issues []*gitea_sdk.Issue
+	for page := 1; len(issues) < int(limit); page++ {
+		opt.ListOptions = gitea_sdk.ListOptions{Page: page, PageSize: pageSize}
+		batch, _, err := client.ListRepoIssues(owner, repo, opt)
+		if err != nil {
+			return nil, nil, fmt.Errorf("get %v/%v/issues err: %v", owner, repo, err)
+		}
+		issues = append(issues, batch...)
+		if len(batch) < pageSize {
+			break
+		}
+	}
+	if len(issues) > int(limit) {
+		issues = issues[:int(limit)]
+	}
+	return issues, nil, nil
+}
+
+func containsFold(names []string, name string) bool {
+	return slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, name) })
+}
+
+// plan works out the changes a bulk edit makes to an issue, skipping those
+// already in place.
+func (a *bulkActions) plan(issue *gitea_sdk.Issue) (*gitea_sdk.EditIssueOption, []int64, []int64, []string) {
+	var (
+		edit    *gitea_sdk.EditIssueOption
+		add     []int64
+		remove  []int64
+		changes []string
+	)
+	has := make(map[int64]bool, len(issue.Labels))
+	for _, l := range issue.Labels {
+		has[l.ID] = true
+	}
+	for _, l := range a.addLabels {
+		if !has[l.id] {
+			add = append(add, l.id)
+			changes = append(changes, "add label "+l.name)
+		}
+	}
+	for _, l := range a.removeLabels {
+		if has[l.id] {
+			remove = append(remove, l.id)
+			changes = append(changes, "remove label "+l.name)
+		}
+	}
+
+	editOpt := &gitea_sdk.EditIssueOption{}
+	if a.state != "" && string(issue.State) != a.state {
+		editOpt.State = ptr.To(gitea_sdk.StateType(a.state))
+		edit = editOpt
+		if a.state == "closed" {
+			changes = append(changes, "close")
+		} else {
+			changes = append(changes, "reopen")
+		}
+	}
+	if a.setMilestone {
+		var current int64
+		if issue.Milestone != nil {
+			current = issue.Milestone.ID
+		}
+		if current != a.milestone {
+			editOpt.Milestone = ptr.To(a.milestone)
+			edit = editOpt
+			if a.milestone == 0 {
+				changes = append(changes, "remove milestone")
+			} else {
+				changes = append(changes, "set milestone "+a.milestoneTitle)
+			}
+		}
+	}
+	if len(a.addAssignees) > 0 || len(a.removeAssignees) > 0 {
+		changed := false
+		assignees := make([]string, 0, len(issue.Assignees))
+		for _, u := range issue.Assignees {
+			if containsFold(a.removeAssignees, u.UserName) {
+				changes = append(changes, "unassign "+u.UserName)
+				changed = true
+				continue
+			}
+			assignees = append(assignees, u.UserName)
+		}
+		for _, name := range a.addAssignees {
+			if !containsFold(assignees, name) {
+				assignees = append(assignees, name)
+				changes = append(changes, "assign "+name)
+				changed = true
+			}
+		}
+		if changed {
+			editOpt.Assignees = assignees
+			edit = editOpt
+		}
+	}
+	if a.comment != "" {
+		changes = append(changes, "comment")
+	}
+	return edit, add, remove, changes
+}
+
+// apply makes the planned changes to an issue. The comment is posted last,
+// as the only change not skipped by plan when already made, so retrying an
+// issue that failed halfway does not post it twice.
+func (a *bulkActions) apply(ctx context.Context, owner, repo string, index int64, edit *gitea_sdk.EditIssueOption, add, remove []int64) error {
+	client := gitea.ClientFromContext(ctx)
+	if len(add) > 0 {
+		if _, _, err := client.AddIssueLabels(owner, repo, index, gitea_sdk.IssueLabelsOption{Labels: add}); err != nil {
+			return fmt.Errorf("add labels err: %v", err)
+		}
+	}
+	for _, id := range remove {
+		if _, err := client.DeleteIssueLabel(owner, repo, index, id); err != nil {
+			return fmt.Errorf("remove label err: %v", err)
+		}
+	}
+	if edit != nil {
+		if _, _, err := client.EditIssue(owner, repo, index, *edit); err != nil {
+			return fmt.Errorf("edit err: %v", err)
+		}
+	}
+	if a.comment != "" {
+		if _, _, err := client.CreateIssueComment(owner, repo, index, gitea_sdk.CreateIssueCommentOption{Body: a.comment}); err != nil {
+			return fmt.Errorf("comment err: %v", err)
+		}
+	}
+	return nil
+}
+
+func BulkEditIssuesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called BulkEditIssuesFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	dryRun, _ := req.GetArguments()["dryRun"].(bool)
+	concurrency, ok := req.GetArguments()["concurrency"].(float64)
+	if !ok {
+		concurrency = defaultBulkConcurrency
+	}
+	if concurrency < 1 || concurrency > maxBulkConcurrency {
+		return to.ErrorResult(fmt.Errorf("concurrency must be between 1 and %d", maxBulkConcurrency))
+	}
+
+	actions, err := bulkActionsFromRequest(ctx, req, owner, repo)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	issues, fetchErrs, err := selectIssues(ctx, req, owner, repo)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+
+	report := &BulkReport{
+		DryRun:   dryRun,
+		Selected: len(issues),
+		Items:    make([]*BulkItem, len(issues)),
+	}
+	sem := make(chan struct{}, int(concurrency))
+	var wg sync.WaitGroup
+	for i, issue := range issues {
+		edit, add, remove, changes := actions.plan(issue)
+		item := &BulkItem{
+			Index:   issue.Index,
+			Title:   issue.Title,
+			Changes: changes,
+		}
+		if changes == nil {
+			item.Changes = []string{}
+		}
+		report.Items[i] = item
+		switch {
+		case fetchErrs[issue.Index] != nil:
+			item.Status = "failed"
+			item.Error = fetchErrs[issue.Index].Error()
+			continue
+		case len(changes) == 0:
+			item.Status = "unchanged"
+			continue
+		case dryRun:
+			item.Status = "planned"
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := actions.apply(ctx, owner, repo, issue.Index, edit, add, remove); err != nil {
+				item.Status = "failed"
+				item.Error = err.Error()
+				return
+			}
+			item.Status = "succeeded"
+		}()
+	}
+	wg.Wait()
+
+	for _, item := range report.Items {
+		switch item.Status {
+		case "succeeded":
+			report.Succeeded++
+		case "failed":
+			report.Failed++
+		case "unchanged":
+			report.Unchanged++
+		}
+	}
+	return to.TextResult(report)
+}
@@ -0,0 +1,118 @@
+package issue
+
+import (
+	"slices"
+	"testing"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+)
+
+func TestBulkPlan(t *testing.T) {
+	bug, docs := bulkLabel{id: 1, name: "bug"}, bulkLabel{id: 2, name: "docs"}
+	issue := &gitea_sdk.Issue{
+		Index:     1,
+		State:     gitea_sdk.StateOpen,
+		Labels:    []*gitea_sdk.Label{{ID: 1, Name: "bug"}},
+		Milestone: &gitea_sdk.Milestone{ID: 7, Title: "v1"},
+		Assignees: []*gitea_sdk.User{{UserName: "alice"}, {UserName: "bob"}},
+	}
+	tests := []struct {
+		name      string
+		actions   bulkActions
+		changes   []string
+		add       []int64
+		remove    []int64
+		edit      bool
+		state     gitea_sdk.StateType
+		milestone *int64
+		assignees []string
+	}{
+		{
+			name:    "label no-ops",
+			actions: bulkActions{addLabels: []bulkLabel{bug}, removeLabels: []bulkLabel{docs}},
+		},
+		{
+			name:    "labels",
+			actions: bulkActions{addLabels: []bulkLabel{bug, docs}, removeLabels: []bulkLabel{bug}},
+			changes: []string{"add label docs", "remove label bug"},
+			add:     []int64{2},
+			remove:  []int64{1},
+		},
+		{
+			name:    "state no-op",
+			actions: bulkActions{state: "open"},
+		},
+		{
+			name:    "close",
+			actions: bulkActions{state: "closed"},
+			changes: []string{"close"},
+			edit:    true,
+			state:   gitea_sdk.StateClosed,
+		},
+		{
+			name:    "same milestone",
+			actions: bulkActions{setMilestone: true, milestone: 7, milestoneTitle: "v1"},
+		},
+		{
+			name:      "milestone 0 removes it",
+			actions:   bulkActions{setMilestone: true, milestone: 0, milestoneTitle: "0"},
+			changes:   []string{"remove milestone"},
+			edit:      true,
+			milestone: new(int64),
+		},
+		{
+			name:      "assignees merge",
+			actions:   bulkActions{addAssignees: []string{"Alice", "carol"}},
+			changes:   []string{"assign carol"},
+			edit:      true,
+			assignees: []string{"alice", "bob", "carol"},
+		},
+		{
+			name:      "assignees removal",
+			actions:   bulkActions{addAssignees: []string{"carol"}, removeAssignees: []string{"BOB", "dave"}},
+			changes:   []string{"unassign bob", "assign carol"},
+			edit:      true,
+			assignees: []string{"alice", "carol"},
+		},
+		{
+			name:    "assignees no-op",
+			actions: bulkActions{addAssignees: []string{"bob"}, removeAssignees: []string{"dave"}},
+		},
+		{
+			name:    "comment comes last",
+			actions: bulkActions{state: "closed", comment: "done"},
+			changes: []string{"close", "comment"},
+			edit:    true,
+			state:   gitea_sdk.StateClosed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edit, add, remove, changes := tt.actions.plan(issue)
+			if !slices.Equal(changes, tt.changes) {
+				t.Errorf("changes = %q, want %q", changes, tt.changes)
+			}
+			if !slices.Equal(add, tt.add) || !slices.Equal(remove, tt.remove) {
+				t.Errorf("add %v remove %v, want add %v remove %v", add, remove, tt.add, tt.remove)
+			}
+			if (edit != nil) != tt.edit {
+				t.Fatalf("edit = %+v, want edit %v", edit, tt.edit)
+			}
+			if edit == nil {
+				return
+			}
+			if tt.state != "" && (edit.State == nil || *edit.State != tt.state) {
+				t.Errorf("state = %v, want %v", edit.State, tt.state)
+			}
+			if tt.state == "" && edit.State != nil {
+				t.Errorf("state = %v, want unchanged", *edit.State)
+			}
+			if (edit.Milestone == nil) != (tt.milestone == nil) || (edit.Milestone != nil && *edit.Milestone != *tt.milestone) {
+				t.Errorf("milestone = %v, want %v", edit.Milestone, tt.milestone)
+			}
+			if !slices.Equal(edit.Assignees, tt.assignees) {
+				t.Errorf("assignees = %v, want %v", edit.Assignees, tt.assignees)
+			}
+		})
+	}
+}
@@ -0,0 +1,451 @@
+package issue
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	ListIssueDependenciesToolName   = "list_issue_dependencies"
+	AddIssueDependencyToolName      = "add_issue_dependency"
+	RemoveIssueDependencyToolName   = "remove_issue_dependency"
+	ListIssueBlocksToolName         = "list_issue_blocks"
+	AddIssueBlockToolName           = "add_issue_block"
+	RemoveIssueBlockToolName        = "remove_issue_block"
+	GetIssueDependencyGraphToolName = "get_issue_dependency_graph"
+)
+
+// issue dependencies have an API since Gitea 1.19
+const dependencyMinVersion = "1.19.0"
+
+var (
+	ListIssueDependenciesTool = mcp.NewTool(
+		ListIssueDependenciesToolName,
+		mcp.WithDescription("List the issues an issue depends on, i.e. the issues blocking it"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithNumber("page", mcp.Description("page number"), mcp.DefaultNumber(1)),
+		mcp.WithNumber("pageSize", mcp.Description("page size"), mcp.DefaultNumber(100)),
+	)
+
+	AddIssueDependencyTool = mcp.NewTool(
+		AddIssueDependencyToolName,
+		mcp.WithDescription("Make an issue depend on another issue, which then blocks it"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithNumber("dependency_index", mcp.Required(), mcp.Description("index of the issue it depends on")),
+		mcp.WithString("dependency_owner", mcp.Description("owner of the issue it depends on, defaults to owner")),
+		mcp.WithString("dependency_repo", mcp.Description("repository of the issue it depends on, defaults to repo")),
+	)
+
+	RemoveIssueDependencyTool = mcp.NewTool(
+		RemoveIssueDependencyToolName,
+		mcp.WithDescription("Remove a dependency of an issue"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithNumber("dependency_index", mcp.Required(), mcp.Description("index of the issue it depends on")),
+		mcp.WithString("dependency_owner", mcp.Description("owner of the issue it depends on, defaults to owner")),
+		mcp.WithString("dependency_repo", mcp.Description("repository of the issue it depends on, defaults to repo")),
+	)
+
+	ListIssueBlocksTool = mcp.NewTool(
+		ListIssueBlocksToolName,
+		mcp.WithDescription("List the issues blocked by an issue"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithNumber("page", mcp.Description("page number"), mcp.DefaultNumber(1)),
+		mcp.WithNumber("pageSize", mcp.Description("page size"), mcp.DefaultNumber(100)),
+	)
+
+	AddIssueBlockTool = mcp.NewTool(
+		AddIssueBlockToolName,
+		mcp.WithDescription("Make an issue block another issue"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithNumber("blocked_index", mcp.Required(), mcp.Description("index of the blocked issue")),
+		mcp.WithString("blocked_owner", mcp.Description("owner of the blocked issue, defaults to owner")),
+		mcp.WithString("blocked_repo", mcp.Description("repository of the blocked issue, defaults to repo")),
+	)
+
+	RemoveIssueBlockTool = mcp.NewTool(
+		RemoveIssueBlockToolName,
+		mcp.WithDescription("Stop an issue from blocking another issue"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithNumber("blocked_index", mcp.Required(), mcp.Description("index of the blocked issue")),
+		mcp.WithString("blocked_owner", mcp.Description("owner of the blocked issue, defaults to owner")),
+		mcp.WithString("blocked_repo", mcp.Description("repository of the blocked issue, defaults to repo")),
+	)
+
+	GetIssueDependencyGraphTool = mcp.NewTool(
+		GetIssueDependencyGraphToolName,
+		mcp.WithDescription("Walk the dependencies of an issue transitively, across repositories, and return the graph with the state of each issue and which open issues are unblocked"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithString("direction", mcp.Description("follow the issues it depends on, the issues it blocks, or both"), mcp.Enum("dependencies", "blocks", "both"), mcp.DefaultString("dependencies")),
+		mcp.WithNumber("maxDepth", mcp.Description("maximum number of hops from the issue"), mcp.DefaultNumber(10)),
+		mcp.WithNumber("maxNodes", mcp.Description("maximum number of issues in the graph"), mcp.DefaultNumber(200)),
+	)
+)
+
+func init() {
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListIssueDependenciesTool,
+		Handler: ListIssueDependenciesFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    AddIssueDependencyTool,
+		Handler: AddIssueDependencyFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    RemoveIssueDependencyTool,
+		Handler: RemoveIssueDependencyFn,
+	})
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListIssueBlocksTool,
+		Handler: ListIssueBlocksFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    AddIssueBlockTool,
+		Handler: AddIssueBlockFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    RemoveIssueBlockTool,
+		Handler: RemoveIssueBlockFn,
+	})
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    GetIssueDependencyGraphTool,
+		Handler: GetIssueDependencyGraphFn,
+	})
+	for _, name := range []string{
+		ListIssueDependenciesToolName,
+		AddIssueDependencyToolName,
+		RemoveIssueDependencyToolName,
+		ListIssueBlocksToolName,
+		AddIssueBlockToolName,
+		RemoveIssueBlockToolName,
+		GetIssueDependencyGraphToolName,
+	} {
+		Tool.RequireVersion(name, dependencyMinVersion)
+	}
+}
+
+// issueMeta identifies an issue in the dependency endpoints.
+type issueMeta struct {
+	Owner string `json:"owner"`
+	Repo  string `json:"repo"`
+	Index int64  `json:"index"`
+}
+
+// issuePath returns the API path of an issue sub-resource, such as its
+// "dependencies" or "blocks".
+func issuePath(owner, repo string, index int64, resource string) string {
+	return fmt.Sprintf("/repos/%s/%s/issues/%d/%s", url.PathEscape(owner), url.PathEscape(repo), index, resource)
+}
+
+func listRelated(ctx context.Context, owner, repo string, index int64, relation string, page, pageSize int) ([]*gitea_sdk.Issue, error) {
+	var issues []*gitea_sdk.Issue
+	path := fmt.Sprintf("%s?page=%d&limit=%d", issuePath(owner, repo, index, relation), page, pageSize)
+	if err := gitea.Do(ctx, "GET", path, nil, &issues); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+func listAllRelated(ctx context.Context, owner, repo string, index int64, relation string) ([]*gitea_sdk.Issue, error) {
+	const pageSize = 50
+	var all []*gitea_sdk.Issue
+	for page := 1; ; page++ {
+		issues, err := listRelated(ctx, owner, repo, index, relation, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, issues...)
+		if len(issues) < pageSize {
+			return all, nil
+		}
+	}
+}
+
+func listRelatedFn(ctx context.Context, req mcp.CallToolRequest, relation string) (*mcp.CallToolResult, error) {
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	index, ok := req.GetArguments()["index"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("index is required"))
+	}
+	page, ok := req.GetArguments()["page"].(float64)
+	if !ok {
+		page = 1
+	}
+	pageSize, ok := req.GetArguments()["pageSize"].(float64)
+	if !ok {
+		pageSize = 100
+	}
+	issues, err := listRelated(ctx, owner, repo, int64(index), relation, int(page), int(pageSize))
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/issues/%v/%v err: %v", owner, repo, int64(index), relation, err))
+	}
+	return to.TextResult(issues)
+}
+
+// changeRelatedFn adds or removes the issue named by the prefix_owner,
+// prefix_repo and prefix_index arguments to the relation of the issue.
+func changeRelatedFn(ctx context.Context, req mcp.CallToolRequest, method, relation, prefix string) (*mcp.CallToolResult, error) {
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	index, ok := req.GetArguments()["index"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("index is required"))
+	}
+	other := issueMeta{Owner: owner, Repo: repo}
+	otherIndex, ok := req.GetArguments()[prefix+"_index"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("%s_index is required", prefix))
+	}
+	other.Index = int64(otherIndex)
+	if v, ok := req.GetArguments()[prefix+"_owner"].(string); ok && v != "" {
+		other.Owner = v
+	}
+	if v, ok := req.GetArguments()[prefix+"_repo"].(string); ok && v != "" {
+		other.Repo = v
+	}
+
+	issue := &gitea_sdk.Issue{}
+	if err := gitea.Do(ctx, method, issuePath(owner, repo, int64(index), relation), other, issue); err != nil {
+		verb := "add"
+		if method == "DELETE" {
+			verb = "delete"
+		}
+		return to.ErrorResult(fmt.Errorf("%s %v/%v/issues/%v/%v err: %v", verb, owner, repo, int64(index), relation, err))
+	}
+	return to.TextResult(issue)
+}
+
+func ListIssueDependenciesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListIssueDependenciesFn")
+	return listRelatedFn(ctx, req, "dependencies")
+}
+
+func AddIssueDependencyFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called AddIssueDependencyFn")
+	return changeRelatedFn(ctx, req, "POST", "dependencies", "dependency")
+}
+
+func RemoveIssueDependencyFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called RemoveIssueDependencyFn")
+	return changeRelatedFn(ctx, req, "DELETE", "dependencies", "dependency")
+}
+
+func ListIssueBlocksFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListIssueBlocksFn")
+	return listRelatedFn(ctx, req, "blocks")
+}
+
+func AddIssueBlockFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called AddIssueBlockFn")
+	return changeRelatedFn(ctx, req, "POST", "blocks", "blocked")
+}
+
+func RemoveIssueBlockFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called RemoveIssueBlockFn")
+	return changeRelatedFn(ctx, req, "DELETE", "blocks", "blocked")
+}
+
+// DependencyNode is an issue of a dependency graph.
+type DependencyNode struct {
+	ID      string `json:"id"`
+	Owner   string `json:"owner"`
+	Repo    string `json:"repo"`
+	Index   int64  `json:"index"`
+	Title   string `json:"title"`
+	State   string `json:"state"`
+	HTMLURL string `json:"html_url"`
+	Depth   int    `json:"depth"`
+	// OpenDependencies counts the open issues this issue depends on, if its
+	// dependencies were walked.
+	OpenDependencies int `json:"open_dependencies"`
+	// Unblocked is set for open issues whose dependencies were walked and
+	// are all closed.
+	Unblocked bool `json:"unblocked"`
+}
+
+// DependencyEdge reads "From depends on To".
+type DependencyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// DependencyGraph is the result of get_issue_dependency_graph.
+type DependencyGraph struct {
+	Root      string            `json:"root"`
+	Nodes     []*DependencyNode `json:"nodes"`
+	Edges     []DependencyEdge  `json:"edges"`
+	Unblocked []string          `json:"unblocked"`
+	// Truncated is set when maxDepth or maxNodes stopped the walk.
+	Truncated bool `json:"truncated"`
+}
+
+func nodeID(owner, repo string, index int64) string {
+	return fmt.Sprintf("%s/%s#%d", owner, repo, index)
+}
+
+func newDependencyNode(issue *gitea_sdk.Issue, owner, repo string, depth int) *DependencyNode {
+	if issue.Repository != nil && issue.Repository.Owner != "" {
+		owner, repo = issue.Repository.Owner, issue.Repository.Name
+	}
+	return &DependencyNode{
+		ID:      nodeID(owner, repo, issue.Index),
+		Owner:   owner,
+		Repo:    repo,
+		Index:   issue.Index,
+		Title:   issue.Title,
+		State:   string(issue.State),
+		HTMLURL: issue.HTMLURL,
+		Depth:   depth,
+	}
+}
+
+func GetIssueDependencyGraphFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called GetIssueDependencyGraphFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	index, ok := req.GetArguments()["index"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("index is required"))
+	}
+	direction, ok := req.GetArguments()["direction"].(string)
+	if !ok {
+		direction = "dependencies"
+	}
+	maxDepth, ok := req.GetArguments()["maxDepth"].(float64)
+	if !ok {
+		maxDepth = 10
+	}
+	maxNodes, ok := req.GetArguments()["maxNodes"].(float64)
+	if !ok {
+		maxNodes = 200
+	}
+
+	root, _, err := gitea.ClientFromContext(ctx).GetIssue(owner, repo, int64(index))
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/issue/%v err: %v", owner, repo, int64(index), err))
+	}
+	listRelated := func(node *DependencyNode, relation string) ([]*gitea_sdk.Issue, error) {
+		return listAllRelated(ctx, node.Owner, node.Repo, node.Index, relation)
+	}
+	graph, err := walkDependencies(newDependencyNode(root, owner, repo, 0), direction, int(maxDepth), int(maxNodes), listRelated)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	return to.TextResult(graph)
+}
+
+// walkDependencies builds the dependency graph around root breadth-first, so
+// the depth and node limits cut the farthest issues. listRelated lists the
+// dependencies or blocks of a node.
+func walkDependencies(root *DependencyNode, direction string, maxDepth, maxNodes int, listRelated func(node *DependencyNode, relation string) ([]*gitea_sdk.Issue, error)) (*DependencyGraph, error) {
+	graph := &DependencyGraph{
+		Root:      root.ID,
+		Nodes:     []*DependencyNode{root},
+		Edges:     []DependencyEdge{},
+		Unblocked: []string{},
+	}
+	nodes := map[string]*DependencyNode{root.ID: root}
+	walked := map[string]bool{}
+	edges := map[DependencyEdge]bool{}
+	// openDeps holds the open dependencies of each node, including those
+	// left out of the graph by maxNodes
+	openDeps := map[string]map[string]bool{}
+
+	queue := []*DependencyNode{root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if node.Depth >= maxDepth {
+			graph.Truncated = true
+			continue
+		}
+		for _, relation := range []string{"dependencies", "blocks"} {
+			if direction != "both" && direction != relation {
+				continue
+			}
+			related, err := listRelated(node, relation)
+			if err != nil {
+				return nil, fmt.Errorf("get %v/%v/issues/%v/%v err: %v", node.Owner, node.Repo, node.Index, relation, err)
+			}
+			if relation == "dependencies" {
+				walked[node.ID] = true
+			}
+			for _, issue := range related {
+				other := newDependencyNode(issue, node.Owner, node.Repo, node.Depth+1)
+				from, dep := node, other
+				if relation == "blocks" {
+					from, dep = other, node
+				}
+				if dep.State == string(gitea_sdk.StateOpen) {
+					if openDeps[from.ID] == nil {
+						openDeps[from.ID] = map[string]bool{}
+					}
+					openDeps[from.ID][dep.ID] = true
+				}
+				if _, ok := nodes[other.ID]; !ok {
+					if len(nodes) >= maxNodes {
+						graph.Truncated = true
+						continue
+					}
+					nodes[other.ID] = other
+					graph.Nodes = append(graph.Nodes, other)
+					queue = append(queue, other)
+				}
+				if e := (DependencyEdge{From: from.ID, To: dep.ID}); !edges[e] {
+					edges[e] = true
+					graph.Edges = append(graph.Edges, e)
+				}
+			}
+		}
+	}
+
+	for _, node := range graph.Nodes {
+		node.OpenDependencies = len(openDeps[node.ID])
+		if walked[node.ID] && node.State == string(gitea_sdk.StateOpen) && node.OpenDependencies == 0 {
+			node.Unblocked = true
+			graph.Unblocked = append(graph.Unblocked, node.ID)
+		}
+	}
+	return graph, nil
+}
@@ -0,0 +1,179 @@
+package issue
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"testing"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+)
+
+// fakeTracker answers listRelated from a map of issue index to the indexes
+// it depends on, in repository o/r.
+type fakeTracker struct {
+	states map[int64]gitea_sdk.StateType
+	deps   map[int64][]int64
+}
+
+func (t fakeTracker) issue(index int64) *gitea_sdk.Issue {
+	return &gitea_sdk.Issue{Index: index, Title: fmt.Sprint("issue ", index), State: t.states[index]}
+}
+
+func (t fakeTracker) listRelated(node *DependencyNode, relation string) ([]*gitea_sdk.Issue, error) {
+	var issues []*gitea_sdk.Issue
+	switch relation {
+	case "dependencies":
+		for _, dep := range t.deps[node.Index] {
+			issues = append(issues, t.issue(dep))
+		}
+	case "blocks":
+		for from, deps := range t.deps {
+			if slices.Contains(deps, node.Index) {
+				issues = append(issues, t.issue(from))
+			}
+		}
+		sort.Slice(issues, func(i, j int) bool { return issues[i].Index < issues[j].Index })
+	}
+	return issues, nil
+}
+
+func TestWalkDependencies(t *testing.T) {
+	open, closed := gitea_sdk.StateOpen, gitea_sdk.StateClosed
+	tests := []struct {
+		name      string
+		tracker   fakeTracker
+		direction string
+		maxDepth  int
+		maxNodes  int
+		nodes     []string
+		edges     []DependencyEdge
+		unblocked []string
+		openDeps  map[string]int
+		truncated bool
+	}{
+		{
+			name: "closed dependency unblocks",
+			tracker: fakeTracker{
+				states: map[int64]gitea_sdk.StateType{1: open, 2: closed},
+				deps:   map[int64][]int64{1: {2}},
+			},
+			direction: "dependencies",
+			maxDepth:  10,
+			maxNodes:  10,
+			nodes:     []string{"o/r#1", "o/r#2"},
+			edges:     []DependencyEdge{{From: "o/r#1", To: "o/r#2"}},
+			unblocked: []string{"o/r#1"},
+			openDeps:  map[string]int{"o/r#1": 0},
+		},
+		{
+			name: "open dependency blocks",
+			tracker: fakeTracker{
+				states: map[int64]gitea_sdk.StateType{1: open, 2: open, 3: closed},
+				deps:   map[int64][]int64{1: {2}, 2: {3}},
+			},
+			direction: "dependencies",
+			maxDepth:  10,
+			maxNodes:  10,
+			nodes:     []string{"o/r#1", "o/r#2", "o/r#3"},
+			edges:     []DependencyEdge{{From: "o/r#1", To: "o/r#2"}, {From: "o/r#2", To: "o/r#3"}},
+			unblocked: []string{"o/r#2"},
+			openDeps:  map[string]int{"o/r#1": 1, "o/r#2": 0},
+		},
+		{
+			name: "dependency cut by maxNodes still blocks",
+			tracker: fakeTracker{
+				states: map[int64]gitea_sdk.StateType{1: open, 2: closed, 3: open},
+				deps:   map[int64][]int64{1: {2, 3}},
+			},
+			direction: "dependencies",
+			maxDepth:  10,
+			maxNodes:  2,
+			nodes:     []string{"o/r#1", "o/r#2"},
+			edges:     []DependencyEdge{{From: "o/r#1", To: "o/r#2"}},
+			unblocked: []string{},
+			openDeps:  map[string]int{"o/r#1": 1},
+			truncated: true,
+		},
+		{
+			name: "maxDepth stops the walk",
+			tracker: fakeTracker{
+				states: map[int64]gitea_sdk.StateType{1: open, 2: open, 3: open},
+				deps:   map[int64][]int64{1: {2}, 2: {3}},
+			},
+			direction: "dependencies",
+			maxDepth:  1,
+			maxNodes:  10,
+			nodes:     []string{"o/r#1", "o/r#2"},
+			edges:     []DependencyEdge{{From: "o/r#1", To: "o/r#2"}},
+			unblocked: []string{},
+			openDeps:  map[string]int{"o/r#1": 1},
+			truncated: true,
+		},
+		{
+			name: "both directions",
+			tracker: fakeTracker{
+				states: map[int64]gitea_sdk.StateType{1: open, 2: closed, 3: open},
+				deps:   map[int64][]int64{1: {2}, 3: {1}},
+			},
+			direction: "both",
+			maxDepth:  10,
+			maxNodes:  10,
+			nodes:     []string{"o/r#1", "o/r#2", "o/r#3"},
+			edges:     []DependencyEdge{{From: "o/r#1", To: "o/r#2"}, {From: "o/r#3", To: "o/r#1"}},
+			unblocked: []string{"o/r#1"},
+			openDeps:  map[string]int{"o/r#1": 0, "o/r#3": 1},
+		},
+		{
+			name: "cycle",
+			tracker: fakeTracker{
+				states: map[int64]gitea_sdk.StateType{1: open, 2: open},
+				deps:   map[int64][]int64{1: {2}, 2: {1}},
+			},
+			direction: "dependencies",
+			maxDepth:  10,
+			maxNodes:  10,
+			nodes:     []string{"o/r#1", "o/r#2"},
+			edges:     []DependencyEdge{{From: "o/r#1", To: "o/r#2"}, {From: "o/r#2", To: "o/r#1"}},
+			unblocked: []string{},
+			openDeps:  map[string]int{"o/r#1": 1, "o/r#2": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newDependencyNode(tt.tracker.issue(1), "o", "r", 0)
+			graph, err := walkDependencies(root, tt.direction, tt.maxDepth, tt.maxNodes, tt.tracker.listRelated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var nodes []string
+			byID := map[string]*DependencyNode{}
+			for _, n := range graph.Nodes {
+				nodes = append(nodes, n.ID)
+				byID[n.ID] = n
+			}
+			if !slices.Equal(nodes, tt.nodes) {
+				t.Errorf("nodes = %v, want %v", nodes, tt.nodes)
+			}
+			if !slices.Equal(graph.Edges, tt.edges) {
+				t.Errorf("edges = %v, want %v", graph.Edges, tt.edges)
+			}
+			for _, e := range graph.Edges {
+				if byID[e.From] == nil || byID[e.To] == nil {
+					t.Errorf("edge %v points outside the graph", e)
+				}
+			}
+			if !slices.Equal(graph.Unblocked, tt.unblocked) {
+				t.Errorf("unblocked = %v, want %v", graph.Unblocked, tt.unblocked)
+			}
+			for id, want := range tt.openDeps {
+				if got := byID[id].OpenDependencies; got != want {
+					t.Errorf("%s open dependencies = %d, want %d", id, got, want)
+				}
+			}
+			if graph.Truncated != tt.truncated {
+				t.Errorf("truncated = %v, want %v", graph.Truncated, tt.truncated)
+			}
+		})
+	}
+}
@@ -0,0 +1,491 @@
+package issue
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitea.com/gitea/gitea-mcp/pkg/flag"
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	ExportIssuesToolName = "export_issues"
+)
+
+const (
+	// ArchiveVersion is the version of the Archive format. It changes when
+	// fields are removed or change meaning.
+	ArchiveVersion = 1
+
+	defaultExportToolLimit = 20
+	maxExportToolLimit     = 100
+
+	exportPageSize = 50
+)
+
+var ExportIssuesTool = mcp.NewTool(
+	ExportIssuesToolName,
+	mcp.WithDescription("Export the issues and pull requests of a repository with their comments and timelines, and the repository's labels and milestones, as a versioned archive. Issues come in order of last update, a page at a time: while truncated is set, pass the returned cursor to get the next page. Pass the cursor of the last page later on to export only what changed since"),
+	mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+	mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+	mcp.WithString("since", mcp.Description("only issues updated since this date, RFC3339 or YYYY-MM-DD; ignored with cursor")),
+	mcp.WithString("cursor", mcp.Description("cursor returned by a previous export, to continue after its last issue")),
+	mcp.WithNumber("limit", mcp.Description(fmt.Sprintf("number of issues to export, at most %d", maxExportToolLimit)), mcp.DefaultNumber(defaultExportToolLimit)),
+	mcp.WithBoolean("timeline", mcp.Description("include the timeline of each issue"), mcp.DefaultBool(true)),
+	mcp.WithBoolean("markdown", mcp.Description("include a markdown rendering of each issue"), mcp.DefaultBool(false)),
+)
+
+func init() {
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ExportIssuesTool,
+		Handler: ExportIssuesFn,
+	})
+}
+
+// Archive is an export of a repository's issue tracker.
+type Archive struct {
+	Version    int        `json:"version"`
+	Host       string     `json:"host"`
+	Owner      string     `json:"owner"`
+	Repo       string     `json:"repo"`
+	ExportedAt time.Time  `json:"exported_at"`
+	Since      *time.Time `json:"since,omitempty"`
+	// Cursor names the last exported issue; an export given it continues
+	// with the issues after it, or updated since.
+	Cursor string `json:"cursor,omitempty"`
+	// Truncated is set when the limit left issues to export after Cursor.
+	Truncated  bool                   `json:"truncated,omitempty"`
+	Labels     []*gitea_sdk.Label     `json:"labels"`
+	Milestones []*gitea_sdk.Milestone `json:"milestones"`
+	Issues     []*ExportedIssue       `json:"issues"`
+}
+
+// ExportedIssue is an issue or pull request in an Archive.
+type ExportedIssue struct {
+	Issue       *gitea_sdk.Issue       `json:"issue"`
+	PullRequest *gitea_sdk.PullRequest `json:"pull_request,omitempty"`
+	Comments    []*gitea_sdk.Comment   `json:"comments"`
+	Timeline    []*TimelineEvent       `json:"timeline,omitempty"`
+	// TimelineTruncated is set when the timeline has more events than
+	// were exported.
+	TimelineTruncated bool   `json:"timeline_truncated,omitempty"`
+	Markdown          string `json:"markdown,omitempty"`
+}
+
+// ExportOptions selects what Export fetches.
+type ExportOptions struct {
+	// Since limits the issues to those updated since, when not zero.
+	Since time.Time
+	// Cursor continues a previous export after its last issue, instead of
+	// Since, when not empty.
+	Cursor string
+	// Limit bounds the issues exported, when not zero.
+	Limit    int
+	Timeline bool
+	Markdown bool
+}
+
+// exportCursor is the position of an issue in the order of export: by time
+// of last update, then by index.
+type exportCursor struct {
+	updated time.Time
+	index   int64
+}
+
+func (c exportCursor) String() string {
+	return fmt.Sprintf("%s,%d", c.updated.UTC().Format(time.RFC3339), c.index)
+}
+
+func parseExportCursor(s string) (exportCursor, error) {
+	updated, index, ok := strings.Cut(s, ",")
+	if !ok {
+		return exportCursor{}, fmt.Errorf("invalid cursor %q", s)
+	}
+	t, err := time.Parse(time.RFC3339, updated)
+	if err != nil {
+		return exportCursor{}, fmt.Errorf("invalid cursor %q: %v", s, err)
+	}
+	i, err := strconv.ParseInt(index, 10, 64)
+	if err != nil {
+		return exportCursor{}, fmt.Errorf("invalid cursor %q: %v", s, err)
+	}
+	return exportCursor{updated: t, index: i}, nil
+}
+
+func (c exportCursor) before(issue *gitea_sdk.Issue) bool {
+	if !c.updated.Equal(issue.Updated) {
+		return c.updated.Before(issue.Updated)
+	}
+	return c.index < issue.Index
+}
+
+// Export fetches the issue tracker of a repository. Labels and milestones
+// are always exported in full. Issues are exported in the order of their
+// last update, starting after opt.Cursor or at opt.Since, up to opt.Limit.
+func Export(ctx context.Context, owner, repo string, opt ExportOptions) (*Archive, error) {
+	client := gitea.ClientFromContext(ctx)
+	archive := &Archive{
+		Version:    ArchiveVersion,
+		Host:       flag.Host,
+		Owner:      owner,
+		Repo:       repo,
+		ExportedAt: time.Now().UTC(),
+		Labels:     []*gitea_sdk.Label{},
+		Milestones: []*gitea_sdk.Milestone{},
+		Issues:     []*ExportedIssue{},
+	}
+	if !opt.Since.IsZero() {
+		archive.Since = &opt.Since
+	}
+
+	const pageSize = 50
+	for page := 1; ; page++ {
+		labels, _, err := client.ListRepoLabels(owner, repo, gitea_sdk.ListLabelsOptions{
+			ListOptions: gitea_sdk.ListOptions{Page: page, PageSize: pageSize},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("get %v/%v/labels err: %v", owner, repo, err)
+		}
+		archive.Labels = append(archive.Labels, labels...)
+		if len(labels) < pageSize {
+			break
+		}
+	}
+	for page := 1; ; page++ {
+		milestones, _, err := client.ListRepoMilestones(owner, repo, gitea_sdk.ListMilestoneOption{
+			ListOptions: gitea_sdk.ListOptions{Page: page, PageSize: pageSize},
+			State:       gitea_sdk.StateAll,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("get %v/%v/milestones err: %v", owner, repo, err)
+		}
+		archive.Milestones = append(archive.Milestones, milestones...)
+		if len(milestones) < pageSize {
+			break
+		}
+	}
+
+	issues, more, err := listExportIssues(ctx, owner, repo, opt)
+	if err != nil {
+		return nil, err
+	}
+	archive.Truncated = more
+	if len(issues) > 0 {
+		last := issues[len(issues)-1]
+		archive.Cursor = exportCursor{updated: last.Updated, index: last.Index}.String()
+	} else if opt.Cursor != "" {
+		// nothing changed, the next export starts from the same issue
+		archive.Cursor = opt.Cursor
+	} else if !opt.Since.IsZero() {
+		archive.Cursor = exportCursor{updated: opt.Since}.String()
+	}
+
+	for _, issue := range issues {
+		exported := &ExportedIssue{Issue: issue}
+		if issue.PullRequest != nil {
+			pr, _, err := client.GetPullRequest(owner, repo, issue.Index)
+			if err != nil {
+				return nil, fmt.Errorf("get %v/%v/pulls/%v err: %v", owner, repo, issue.Index, err)
+			}
+			exported.PullRequest = pr
+		}
+		comments, _, err := client.ListIssueComments(owner, repo, issue.Index, gitea_sdk.ListIssueCommentOptions{
+			ListOptions: gitea_sdk.ListOptions{Page: -1},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("get %v/%v/issues/%v/comments err: %v", owner, repo, issue.Index, err)
+		}
+		exported.Comments = comments
+		if opt.Timeline {
+			exported.Timeline, exported.TimelineTruncated, err = listTimeline(ctx, owner, repo, issue.Index, url.Values{})
+			if err != nil {
+				return nil, fmt.Errorf("get %v/%v/issues/%v/timeline err: %v", owner, repo, issue.Index, err)
+			}
+		}
+		if opt.Markdown {
+			exported.Markdown = exported.RenderMarkdown()
+		}
+		archive.Issues = append(archive.Issues, exported)
+	}
+	return archive, nil
+}
+
+// listExportIssues lists up to opt.Limit issues in the order of export, and
+// reports whether more issues follow them. The API can't sort by update time,
+// so issues are listed by windows of update time: a window holding more
+// issues than still fit is cut before the first listed issue that doesn't,
+// and the next window grows, so a limited export only lists about opt.Limit
+// issues however many were updated.
+func listExportIssues(ctx context.Context, owner, repo string, opt ExportOptions) ([]*gitea_sdk.Issue, bool, error) {
+	from := opt.Since.Truncate(time.Second)
+	var cursor *exportCursor
+	if opt.Cursor != "" {
+		c, err := parseExportCursor(opt.Cursor)
+		if err != nil {
+			return nil, false, err
+		}
+		cursor = &c
+		// issues updated in the same second as the cursor but with a
+		// higher index are still to come
+		from = c.updated
+	}
+	if from.IsZero() {
+		from = time.Unix(0, 0)
+	}
+	list := func(lo, hi time.Time, limit int) ([]*gitea_sdk.Issue, bool, error) {
+		return listIssueWindow(ctx, owner, repo, lo, hi, limit, cursor)
+	}
+
+	if opt.Limit <= 0 {
+		issues, _, err := list(from, time.Time{}, -1)
+		sortExportIssues(issues)
+		return issues, false, err
+	}
+
+	end := time.Now().Truncate(time.Second)
+	var issues []*gitea_sdk.Issue
+	lo, hi := from, end
+	// cut is set when an issue is known to follow the window
+	cut := false
+	for !lo.After(end) {
+		remaining := opt.Limit - len(issues)
+		batch, full, err := list(lo, hi, remaining)
+		if err != nil {
+			return nil, false, err
+		}
+		if full {
+			if hi.After(lo) {
+				sortExportIssues(batch)
+				hi = batch[remaining].Updated.Truncate(time.Second).Add(-time.Second)
+				if hi.Before(lo) {
+					hi = lo
+				}
+				cut = true
+				continue
+			}
+			// the issues of a single second can't be told apart by
+			// window, list them all
+			if batch, _, err = list(lo, hi, -1); err != nil {
+				return nil, false, err
+			}
+		}
+		issues = append(issues, batch...)
+		if len(issues) >= opt.Limit {
+			break
+		}
+		span := max(2*hi.Sub(lo), time.Second)
+		lo = hi.Add(time.Second)
+		hi = lo.Add(span)
+		if hi.After(end) {
+			hi = end
+		}
+		cut = false
+	}
+
+	sortExportIssues(issues)
+	switch {
+	case len(issues) > opt.Limit:
+		return issues[:opt.Limit], true, nil
+	case cut:
+		return issues, true, nil
+	case len(issues) < opt.Limit:
+		return issues, false, nil
+	}
+	// the limit is reached exactly, look for any issue updated later
+	later, _, err := list(hi.Add(time.Second), time.Time{}, 0)
+	return issues, len(later) > 0, err
+}
+
+// listIssueWindow lists the issues after cursor updated from lo to hi, both
+// inclusive to the second; a zero hi is open-ended. With limit not negative
+// it stops, reporting full, once more than limit issues are found and more
+// pages follow. A window listed in one page is cheap, so it is taken whole.
+func listIssueWindow(ctx context.Context, owner, repo string, lo, hi time.Time, limit int, cursor *exportCursor) ([]*gitea_sdk.Issue, bool, error) {
+	opt := gitea_sdk.ListIssueOption{State: gitea_sdk.StateAll}
+	// widen the query by a second, in case since and before are exclusive
+	if lo.After(time.Unix(0, 0)) {
+		opt.Since = lo.Add(-time.Second)
+	}
+	if !hi.IsZero() {
+		opt.Before = hi.Add(time.Second)
+	}
+	var issues []*gitea_sdk.Issue
+	for page := 1; ; page++ {
+		opt.ListOptions = gitea_sdk.ListOptions{Page: page, PageSize: exportPageSize}
+		batch, _, err := gitea.ClientFromContext(ctx).ListRepoIssues(owner, repo, opt)
+		if err != nil {
+			return nil, false, fmt.Errorf("get %v/%v/issues err: %v", owner, repo, err)
+		}
+		for _, issue := range batch {
+			updated := issue.Updated.Truncate(time.Second)
+			if updated.Before(lo) || (!hi.IsZero() && updated.After(hi)) {
+				continue
+			}
+			if cursor == nil || cursor.before(issue) {
+				issues = append(issues, issue)
+			}
+		}
+		if len(batch) < exportPageSize {
+			return issues, false, nil
+		}
+		if limit >= 0 && len(issues) > limit {
+			return issues, true, nil
+		}
+	}
+}
+
+// sortExportIssues sorts issues in the order of export, by time of last
+// update, then by index.
+func sortExportIssues(issues []*gitea_sdk.Issue) {
+	sort.Slice(issues, func(i, j int) bool {
+		if !issues[i].Updated.Equal(issues[j].Updated) {
+			return issues[i].Updated.Before(issues[j].Updated)
+		}
+		return issues[i].Index < issues[j].Index
+	})
+}
+
+// Merge adds the issues of a later, incremental export to a, replacing the
+// issues it has again. Labels and milestones are taken from the later
+// export, which has them all.
+func (a *Archive) Merge(later *Archive) {
+	byIndex := make(map[int64]int, len(a.Issues))
+	for i, e := range a.Issues {
+		byIndex[e.Issue.Index] = i
+	}
+	for _, e := range later.Issues {
+		if i, ok := byIndex[e.Issue.Index]; ok {
+			a.Issues[i] = e
+			continue
+		}
+		a.Issues = append(a.Issues, e)
+	}
+	sort.Slice(a.Issues, func(i, j int) bool { return a.Issues[i].Issue.Index < a.Issues[j].Issue.Index })
+	a.Labels = later.Labels
+	a.Milestones = later.Milestones
+	a.ExportedAt = later.ExportedAt
+	a.Cursor = later.Cursor
+	a.Truncated = later.Truncated
+}
+
+// RenderMarkdown renders the issue, its comments and its timeline as a
+// markdown document.
+func (e *ExportedIssue) RenderMarkdown() string {
+	issue := e.Issue
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s (#%d)\n\n", issue.Title, issue.Index)
+
+	kind := "Issue"
+	if e.PullRequest != nil || issue.PullRequest != nil {
+		kind = "Pull request"
+	}
+	fmt.Fprintf(&b, "- **Type:** %s\n", kind)
+	state := string(issue.State)
+	if e.PullRequest != nil && e.PullRequest.HasMerged {
+		state = "merged"
+	}
+	fmt.Fprintf(&b, "- **State:** %s\n", state)
+	fmt.Fprintf(&b, "- **Author:** %s\n", userName(issue.Poster))
+	fmt.Fprintf(&b, "- **Created:** %s\n", issue.Created.Format(time.RFC3339))
+	fmt.Fprintf(&b, "- **Updated:** %s\n", issue.Updated.Format(time.RFC3339))
+	if issue.Closed != nil {
+		fmt.Fprintf(&b, "- **Closed:** %s\n", issue.Closed.Format(time.RFC3339))
+	}
+	if e.PullRequest != nil && e.PullRequest.Head != nil && e.PullRequest.Base != nil {
+		fmt.Fprintf(&b, "- **Branches:** %s → %s\n", e.PullRequest.Head.Ref, e.PullRequest.Base.Ref)
+	}
+	if len(issue.Labels) > 0 {
+		names := make([]string, 0, len(issue.Labels))
+		for _, l := range issue.Labels {
+			names = append(names, l.Name)
+		}
+		fmt.Fprintf(&b, "- **Labels:** %s\n", strings.Join(names, ", "))
+	}
+	if issue.Milestone != nil {
+		fmt.Fprintf(&b, "- **Milestone:** %s\n", issue.Milestone.Title)
+	}
+	if len(issue.Assignees) > 0 {
+		names := make([]string, 0, len(issue.Assignees))
+		for _, u := range issue.Assignees {
+			names = append(names, u.UserName)
+		}
+		fmt.Fprintf(&b, "- **Assignees:** %s\n", strings.Join(names, ", "))
+	}
+	if issue.HTMLURL != "" {
+		fmt.Fprintf(&b, "- **URL:** %s\n", issue.HTMLURL)
+	}
+
+	b.WriteString("\n")
+	if body := strings.TrimSpace(issue.Body); body != "" {
+		b.WriteString(body + "\n")
+	} else {
+		b.WriteString("_No description provided._\n")
+	}
+
+	if len(e.Comments) > 0 {
+		b.WriteString("\n## Comments\n")
+		for _, c := range e.Comments {
+			author := userName(c.Poster)
+			if c.OriginalAuthor != "" {
+				author = c.OriginalAuthor
+			}
+			fmt.Fprintf(&b, "\n### %s commented on %s\n\n%s\n", author, c.Created.Format(time.RFC3339), strings.TrimSpace(c.Body))
+		}
+	}
+
+	if len(e.Timeline) > 0 {
+		b.WriteString("\n## Timeline\n\n")
+		for _, event := range e.Timeline {
+			fmt.Fprintf(&b, "- %s %s %s\n", event.Created.Format(time.RFC3339), eventUser(event), describeEvent(event))
+		}
+		if e.TimelineTruncated {
+			fmt.Fprintf(&b, "\n_The timeline has more than %d events, later ones are left out._\n", maxTimelineEvents)
+		}
+	}
+	return b.String()
+}
+
+func ExportIssuesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ExportIssuesFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	opt := ExportOptions{Timeline: true, Limit: defaultExportToolLimit}
+	if v, ok := req.GetArguments()["since"].(string); ok && v != "" {
+		since, err := to.Time(v)
+		if err != nil {
+			return to.ErrorResult(fmt.Errorf("since: %v", err))
+		}
+		opt.Since = since
+	}
+	opt.Cursor, _ = req.GetArguments()["cursor"].(string)
+	if limit, ok := req.GetArguments()["limit"].(float64); ok && limit > 0 {
+		opt.Limit = min(int(limit), maxExportToolLimit)
+	}
+	if v, ok := req.GetArguments()["timeline"].(bool); ok {
+		opt.Timeline = v
+	}
+	opt.Markdown, _ = req.GetArguments()["markdown"].(bool)
+
+	archive, err := Export(ctx, owner, repo, opt)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	return to.TextResult(archive)
+}
@@ -0,0 +1,199 @@
+package issue
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"gitea.com/gitea/gitea-mcp/pkg/flag"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+	"go.uber.org/zap"
+)
+
+func TestExportCursor(t *testing.T) {
+	day := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	c := exportCursor{updated: day, index: 7}
+	if got := c.String(); got != "2026-01-02T00:00:00Z,7" {
+		t.Errorf("String() = %q", got)
+	}
+	parsed, err := parseExportCursor(c.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.updated.Equal(c.updated) || parsed.index != c.index {
+		t.Errorf("parseExportCursor(%q) = %v, want %v", c, parsed, c)
+	}
+	for _, s := range []string{"", "2026-01-02", "2026-01-02T00:00:00Z", "yesterday,1", "2026-01-02T00:00:00Z,x"} {
+		if _, err := parseExportCursor(s); err == nil {
+			t.Errorf("parseExportCursor(%q) succeeded", s)
+		}
+	}
+
+	tests := []struct {
+		updated time.Time
+		index   int64
+		want    bool
+	}{
+		{day.Add(-time.Second), 9, false},
+		{day, 6, false},
+		{day, 7, false},
+		{day, 8, true},
+		{day.Add(time.Second), 1, true},
+	}
+	for _, tt := range tests {
+		if got := c.before(&gitea_sdk.Issue{Updated: tt.updated, Index: tt.index}); got != tt.want {
+			t.Errorf("before(%v, #%d) = %v, want %v", tt.updated, tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestArchiveMerge(t *testing.T) {
+	exported := func(index int64, title string) *ExportedIssue {
+		return &ExportedIssue{Issue: &gitea_sdk.Issue{Index: index, Title: title}}
+	}
+	a := &Archive{
+		Cursor:    "2026-01-02T00:00:00Z,2",
+		Truncated: true,
+		Labels:    []*gitea_sdk.Label{{Name: "old"}},
+		Issues:    []*ExportedIssue{exported(1, "one"), exported(2, "two")},
+	}
+	later := &Archive{
+		ExportedAt: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		Cursor:     "2026-01-04T00:00:00Z,2",
+		Labels:     []*gitea_sdk.Label{{Name: "new"}},
+		Issues:     []*ExportedIssue{exported(3, "three"), exported(2, "two edited")},
+	}
+	a.Merge(later)
+
+	var titles []string
+	for _, e := range a.Issues {
+		titles = append(titles, e.Issue.Title)
+	}
+	if want := []string{"one", "two edited", "three"}; !slices.Equal(titles, want) {
+		t.Errorf("issues = %v, want %v", titles, want)
+	}
+	if a.Cursor != later.Cursor || !a.ExportedAt.Equal(later.ExportedAt) {
+		t.Errorf("cursor %q at %v, want %q at %v", a.Cursor, a.ExportedAt, later.Cursor, later.ExportedAt)
+	}
+	if a.Truncated {
+		t.Error("truncated is still set after a complete export")
+	}
+	if len(a.Labels) != 1 || a.Labels[0].Name != "new" {
+		t.Errorf("labels = %v, want those of the later export", a.Labels)
+	}
+}
+
+// fakeIssueServer answers the API requests of Export for repository o/r.
+// Like Gitea it lists issues newest first, with since and before inclusive.
+type fakeIssueServer struct {
+	issues       []*gitea_sdk.Issue
+	listRequests int
+}
+
+func (f *fakeIssueServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var result any = []any{}
+	switch path := r.URL.Path; {
+	case path == "/api/v1/version":
+		result = map[string]string{"version": "1.23.0"}
+	case path == "/api/v1/repos/o/r/issues":
+		f.listRequests++
+		result = f.list(r.URL.Query())
+	case path == "/api/v1/repos/o/r/labels", path == "/api/v1/repos/o/r/milestones", strings.HasSuffix(path, "/comments"):
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+func (f *fakeIssueServer) list(query map[string][]string) []*gitea_sdk.Issue {
+	get := func(key string) string {
+		if v := query[key]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	since, _ := time.Parse(time.RFC3339, get("since"))
+	before, _ := time.Parse(time.RFC3339, get("before"))
+	var issues []*gitea_sdk.Issue
+	for _, issue := range f.issues {
+		if (get("since") == "" || !issue.Updated.Before(since)) && (get("before") == "" || !issue.Updated.After(before)) {
+			issues = append(issues, issue)
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Index > issues[j].Index })
+	page, _ := strconv.Atoi(get("page"))
+	limit, _ := strconv.Atoi(get("limit"))
+	start := min((page-1)*limit, len(issues))
+	return issues[start:min(start+limit, len(issues))]
+}
+
+func TestExportPages(t *testing.T) {
+	log.SetDefault(zap.NewNop())
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := &fakeIssueServer{}
+	for i := int64(1); i <= 2000; i++ {
+		// issue i and i+1500 are updated in the same second
+		fake.issues = append(fake.issues, &gitea_sdk.Issue{Index: i, Updated: base.Add(time.Duration(i*37%1500) * time.Hour)})
+	}
+	for i := int64(2001); i <= 2060; i++ {
+		// more issues than fit in a page updated in one second
+		fake.issues = append(fake.issues, &gitea_sdk.Issue{Index: i, Updated: base.Add(500 * time.Hour)})
+	}
+	want := slices.Clone(fake.issues)
+	sortExportIssues(want)
+
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	host := flag.Host
+	flag.Host = srv.URL
+	defer func() { flag.Host = host }()
+
+	const limit = 20
+	var got []int64
+	cursor := ""
+	calls := 0
+	for {
+		calls++
+		if calls > 2*len(want)/limit {
+			t.Fatalf("export does not finish, at %d issues", len(got))
+		}
+		archive, err := Export(context.Background(), "o", "r", ExportOptions{Cursor: cursor, Limit: limit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(archive.Issues) > limit {
+			t.Fatalf("export of %d issues, want at most %d", len(archive.Issues), limit)
+		}
+		for _, e := range archive.Issues {
+			got = append(got, e.Issue.Index)
+		}
+		cursor = archive.Cursor
+		if !archive.Truncated {
+			break
+		}
+	}
+
+	var wantIndexes []int64
+	for _, issue := range want {
+		wantIndexes = append(wantIndexes, issue.Index)
+	}
+	if !slices.Equal(got, wantIndexes) {
+		t.Errorf("exported %v, want %v", got, wantIndexes)
+	}
+	// listing every issue updated since the cursor would take 21 requests
+	// per export on average
+	if perCall := float64(fake.listRequests) / float64(calls); perCall > 6 {
+		t.Errorf("%d list requests for %d exports, want fewer", fake.listRequests, calls)
+	}
+}
@@ -15,7 +15,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-var Tool = tool.New()
+var Tool = tool.New(tool.ScopeIssue)
 
 const (
 	GetIssueByIndexToolName         = "get_issue_by_index"
@@ -25,6 +25,7 @@ const (
 	EditIssueToolName               = "edit_issue"
 	EditIssueCommentToolName        = "edit_issue_comment"
 	GetIssueCommentsByIndexToolName = "get_issue_comments_by_index"
+	ListRepoCommentsToolName        = "list_repo_comments"
 )
 
 var (
@@ -42,6 +43,15 @@ var (
 		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
 		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
 		mcp.WithString("state", mcp.Description("issue state"), mcp.DefaultString("all")),
+		mcp.WithString("type", mcp.Description("whether to list issues, pull requests or both"), mcp.Enum("issues", "pulls", "all"), mcp.DefaultString("all")),
+		mcp.WithArray("labels", mcp.Description("only issues with all of these label names"), mcp.Items(map[string]interface{}{"type": "string"})),
+		mcp.WithArray("milestones", mcp.Description("only issues in one of these milestones, by title or ID"), mcp.Items(map[string]interface{}{"type": "string"})),
+		mcp.WithString("q", mcp.Description("search keyword in title and body")),
+		mcp.WithString("assignee", mcp.Description("only issues assigned to this username")),
+		mcp.WithString("created_by", mcp.Description("only issues created by this username")),
+		mcp.WithString("mentioned_by", mcp.Description("only issues mentioning this username")),
+		mcp.WithString("since", mcp.Description("only issues updated at or after this time, YYYY-MM-DD or RFC 3339")),
+		mcp.WithString("before", mcp.Description("only issues updated at or before this time, YYYY-MM-DD or RFC 3339")),
 		mcp.WithNumber("page", mcp.Description("page number"), mcp.DefaultNumber(1)),
 		mcp.WithNumber("pageSize", mcp.Description("page size"), mcp.DefaultNumber(100)),
 	)
@@ -53,6 +63,12 @@ var (
 		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
 		mcp.WithString("title", mcp.Required(), mcp.Description("issue title")),
 		mcp.WithString("body", mcp.Required(), mcp.Description("issue body")),
+		mcp.WithArray("assignees", mcp.Description("usernames to assign to this issue"), mcp.Items(map[string]interface{}{"type": "string"})),
+		mcp.WithArray("labels", mcp.Description("label names, repository or organization labels"), mcp.Items(map[string]interface{}{"type": "string"})),
+		mcp.WithNumber("milestone", tool.NumberOrString(), mcp.Description("milestone ID or title")),
+		mcp.WithString("due_date", mcp.Description("due date, YYYY-MM-DD or RFC 3339")),
+		mcp.WithString("ref", mcp.Description("branch or tag the issue refers to")),
+		mcp.WithBoolean("closed", mcp.Description("create the issue closed"), mcp.DefaultBool(false)),
 	)
 
 	CreateIssueCommentTool = mcp.NewTool(
@@ -73,7 +89,7 @@ var (
 		mcp.WithString("title", mcp.Description("issue title"), mcp.DefaultString("")),
 		mcp.WithString("body", mcp.Description("issue body content")),
 		mcp.WithArray("assignees", mcp.Description("usernames to assign to this issue"), mcp.Items(map[string]interface{}{"type": "string"})),
-		mcp.WithNumber("milestone", mcp.Description("milestone number")),
+		mcp.WithNumber("milestone", tool.NumberOrString(), mcp.Description("milestone ID or title, 0 removes the milestone")),
 		mcp.WithString("state", mcp.Description("issue state, one of open, closed, all")),
 	)
 
@@ -92,6 +108,21 @@ var (
 		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
 		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
 		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithString("since", mcp.Description("only comments updated at or after this time, YYYY-MM-DD or RFC 3339")),
+		mcp.WithString("before", mcp.Description("only comments updated at or before this time, YYYY-MM-DD or RFC 3339")),
+		mcp.WithNumber("page", mcp.Description("page number"), mcp.DefaultNumber(1)),
+		mcp.WithNumber("pageSize", mcp.Description("page size"), mcp.DefaultNumber(100)),
+	)
+
+	ListRepoCommentsTool = mcp.NewTool(
+		ListRepoCommentsToolName,
+		mcp.WithDescription("List the issue and pull request comments of a repository, oldest first"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithString("since", mcp.Description("only comments updated at or after this time, YYYY-MM-DD or RFC 3339")),
+		mcp.WithString("before", mcp.Description("only comments updated at or before this time, YYYY-MM-DD or RFC 3339")),
+		mcp.WithNumber("page", mcp.Description("page number"), mcp.DefaultNumber(1)),
+		mcp.WithNumber("pageSize", mcp.Description("page size"), mcp.DefaultNumber(100)),
 	)
 )
 
@@ -124,6 +155,10 @@ func init() {
 		Tool:    GetIssueCommentsByIndexTool,
 		Handler: GetIssueCommentsByIndexFn,
 	})
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListRepoCommentsTool,
+		Handler: ListRepoCommentsFn,
+	})
 }
 
 func GetIssueByIndexFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -140,7 +175,7 @@ func GetIssueByIndexFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallT
 	if !ok {
 		return to.ErrorResult(fmt.Errorf("index is required"))
 	}
-	issue, _, err := gitea.Client().GetIssue(owner, repo, int64(index))
+	issue, _, err := gitea.ClientFromContext(ctx).GetIssue(owner, repo, int64(index))
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("get %v/%v/issue/%v err: %v", owner, repo, int64(index), err))
 	}
@@ -158,9 +193,22 @@ func ListRepoIssuesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallTo
 	if !ok {
 		return to.ErrorResult(fmt.Errorf("repo is required"))
 	}
+	opt, err := listIssueOptions(req, "all", "all")
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	issues, _, err := gitea.ClientFromContext(ctx).ListRepoIssues(owner, repo, opt)
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/issues err: %v", owner, repo, err))
+	}
+	return to.TextResult(issues)
+}
+
+// listIssueOptions reads the filter and paging arguments of list_repo_issues.
+func listIssueOptions(req mcp.CallToolRequest, defaultState, defaultType string) (gitea_sdk.ListIssueOption, error) {
 	state, ok := req.GetArguments()["state"].(string)
 	if !ok {
-		state = "all"
+		state = defaultState
 	}
 	page, ok := req.GetArguments()["page"].(float64)
 	if !ok {
@@ -170,18 +218,42 @@ func ListRepoIssuesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallTo
 	if !ok {
 		pageSize = 100
 	}
+	issueType, ok := req.GetArguments()["type"].(string)
+	if !ok {
+		issueType = defaultType
+	}
+	if issueType == "all" {
+		issueType = ""
+	}
 	opt := gitea_sdk.ListIssueOption{
-		State: gitea_sdk.StateType(state),
+		State:      gitea_sdk.StateType(state),
+		Type:       gitea_sdk.IssueType(issueType),
+		Labels:     to.Strings(req.GetArguments()["labels"]),
+		Milestones: to.Strings(req.GetArguments()["milestones"]),
 		ListOptions: gitea_sdk.ListOptions{
 			Page:     int(page),
 			PageSize: int(pageSize),
 		},
 	}
-	issues, _, err := gitea.Client().ListRepoIssues(owner, repo, opt)
-	if err != nil {
-		return to.ErrorResult(fmt.Errorf("get %v/%v/issues err: %v", owner, repo, err))
-	}
-	return to.TextResult(issues)
+	opt.KeyWord, _ = req.GetArguments()["q"].(string)
+	opt.AssignedBy, _ = req.GetArguments()["assignee"].(string)
+	opt.CreatedBy, _ = req.GetArguments()["created_by"].(string)
+	opt.MentionedBy, _ = req.GetArguments()["mentioned_by"].(string)
+	if since, ok := req.GetArguments()["since"].(string); ok && since != "" {
+		t, err := to.Time(since)
+		if err != nil {
+			return opt, err
+		}
+		opt.Since = t
+	}
+	if before, ok := req.GetArguments()["before"].(string); ok && before != "" {
+		t, err := to.Time(before)
+		if err != nil {
+			return opt, err
+		}
+		opt.Before = t
+	}
+	return opt, nil
 }
 
 func CreateIssueFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -202,10 +274,33 @@ func CreateIssueFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolR
 	if !ok {
 		return to.ErrorResult(fmt.Errorf("body is required"))
 	}
-	issue, _, err := gitea.Client().CreateIssue(owner, repo, gitea_sdk.CreateIssueOption{
-		Title: title,
-		Body:  body,
-	})
+	opt := gitea_sdk.CreateIssueOption{
+		Title:     title,
+		Body:      body,
+		Assignees: to.Strings(req.GetArguments()["assignees"]),
+	}
+	opt.Ref, _ = req.GetArguments()["ref"].(string)
+	opt.Closed, _ = req.GetArguments()["closed"].(bool)
+	if labels := to.Strings(req.GetArguments()["labels"]); len(labels) > 0 {
+		ids, err := resolveLabelIDs(ctx, owner, repo, labels)
+		if err != nil {
+			return to.ErrorResult(err)
+		}
+		opt.Labels = ids
+	}
+	milestone, err := gitea.ResolveMilestoneID(ctx, owner, repo, req.GetArguments()["milestone"])
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	opt.Milestone = milestone
+	if dueDate, ok := req.GetArguments()["due_date"].(string); ok && dueDate != "" {
+		deadline, err := to.Time(dueDate)
+		if err != nil {
+			return to.ErrorResult(err)
+		}
+		opt.Deadline = &deadline
+	}
+	issue, _, err := gitea.ClientFromContext(ctx).CreateIssue(owner, repo, opt)
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("create %v/%v/issue err: %v", owner, repo, err))
 	}
@@ -234,7 +329,7 @@ func CreateIssueCommentFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.Ca
 	opt := gitea_sdk.CreateIssueCommentOption{
 		Body: body,
 	}
-	issueComment, _, err := gitea.Client().CreateIssueComment(owner, repo, int64(index), opt)
+	issueComment, _, err := gitea.ClientFromContext(ctx).CreateIssueComment(owner, repo, int64(index), opt)
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("create %v/%v/issue/%v/comment err: %v", owner, repo, int64(index), err))
 	}
@@ -267,20 +362,22 @@ func EditIssueFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolRes
 	if ok {
 		opt.Body = ptr.To(body)
 	}
-	assignees, ok := req.GetArguments()["assignees"].([]string)
-	if ok {
-		opt.Assignees = assignees
+	if _, ok := req.GetArguments()["assignees"]; ok {
+		opt.Assignees = to.Strings(req.GetArguments()["assignees"])
 	}
-	milestone, ok := req.GetArguments()["milestone"].(float64)
-	if ok {
-		opt.Milestone = ptr.To(int64(milestone))
+	if milestone := req.GetArguments()["milestone"]; gitea.MilestoneGiven(milestone) {
+		id, err := gitea.ResolveMilestoneID(ctx, owner, repo, milestone)
+		if err != nil {
+			return to.ErrorResult(err)
+		}
+		opt.Milestone = ptr.To(id)
 	}
 	state, ok := req.GetArguments()["state"].(string)
 	if ok {
 		opt.State = ptr.To(gitea_sdk.StateType(state))
 	}
 
-	issue, _, err := gitea.Client().EditIssue(owner, repo, int64(index), opt)
+	issue, _, err := gitea.ClientFromContext(ctx).EditIssue(owner, repo, int64(index), opt)
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("edit %v/%v/issue/%v err: %v", owner, repo, int64(index), err))
 	}
@@ -309,7 +406,7 @@ func EditIssueCommentFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.Call
 	opt := gitea_sdk.EditIssueCommentOption{
 		Body: body,
 	}
-	issueComment, _, err := gitea.Client().EditIssueComment(owner, repo, int64(commentID), opt)
+	issueComment, _, err := gitea.ClientFromContext(ctx).EditIssueComment(owner, repo, int64(commentID), opt)
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("edit %v/%v/issues/comments/%v err: %v", owner, repo, int64(commentID), err))
 	}
@@ -331,11 +428,75 @@ func GetIssueCommentsByIndexFn(ctx context.Context, req mcp.CallToolRequest) (*m
 	if !ok {
 		return to.ErrorResult(fmt.Errorf("index is required"))
 	}
-	opt := gitea_sdk.ListIssueCommentOptions{}
-	issue, _, err := gitea.Client().ListIssueComments(owner, repo, int64(index), opt)
+	opt, err := commentListOptions(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	// Gitea doesn't page the comments of an issue, so fetch them all and
+	// page here
+	page := opt.ListOptions
+	opt.ListOptions = gitea_sdk.ListOptions{Page: -1}
+	comments, _, err := gitea.ClientFromContext(ctx).ListIssueComments(owner, repo, int64(index), opt)
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("get %v/%v/issues/%v/comments err: %v", owner, repo, int64(index), err))
 	}
+	start := min((page.Page-1)*page.PageSize, len(comments))
+	end := min(start+page.PageSize, len(comments))
 
-	return to.TextResult(issue)
+	return to.TextResult(comments[start:end])
+}
+
+func ListRepoCommentsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListRepoCommentsFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	opt, err := commentListOptions(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	comments, _, err := gitea.ClientFromContext(ctx).ListRepoIssueComments(owner, repo, opt)
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/issues/comments err: %v", owner, repo, err))
+	}
+	return to.TextResult(comments)
+}
+
+// commentListOptions reads the since, before, page and pageSize arguments of
+// the comment list tools.
+func commentListOptions(req mcp.CallToolRequest) (gitea_sdk.ListIssueCommentOptions, error) {
+	page, ok := req.GetArguments()["page"].(float64)
+	if !ok || page < 1 {
+		page = 1
+	}
+	pageSize, ok := req.GetArguments()["pageSize"].(float64)
+	if !ok || pageSize < 1 {
+		pageSize = 100
+	}
+	opt := gitea_sdk.ListIssueCommentOptions{
+		ListOptions: gitea_sdk.ListOptions{
+			Page:     int(page),
+			PageSize: int(pageSize),
+		},
+	}
+	if since, ok := req.GetArguments()["since"].(string); ok && since != "" {
+		t, err := to.Time(since)
+		if err != nil {
+			return opt, err
+		}
+		opt.Since = t
+	}
+	if before, ok := req.GetArguments()["before"].(string); ok && before != "" {
+		t, err := to.Time(before)
+		if err != nil {
+			return opt, err
+		}
+		opt.Before = t
+	}
+	return opt, nil
 }
@@ -0,0 +1,561 @@
+package issue
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/ptr"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+	"gitea.com/gitea/gitea-mcp/pkg/tool"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	ListRepoLabelsToolName     = "list_repo_labels"
+	CreateRepoLabelToolName    = "create_repo_label"
+	EditRepoLabelToolName      = "edit_repo_label"
+	DeleteRepoLabelToolName    = "delete_repo_label"
+	ListOrgLabelsToolName      = "list_org_labels"
+	CreateOrgLabelToolName     = "create_org_label"
+	EditOrgLabelToolName       = "edit_org_label"
+	DeleteOrgLabelToolName     = "delete_org_label"
+	AddIssueLabelsToolName     = "add_issue_labels"
+	RemoveIssueLabelsToolName  = "remove_issue_labels"
+	ReplaceIssueLabelsToolName = "replace_issue_labels"
+	ClearIssueLabelsToolName   = "clear_issue_labels"
+)
+
+var labelNames = mcp.Items(map[string]interface{}{"type": "string"})
+
+var (
+	ListRepoLabelsTool = mcp.NewTool(
+		ListRepoLabelsToolName,
+		mcp.WithDescription("List repository labels"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("page", mcp.Description("page number"), mcp.DefaultNumber(1)),
+		mcp.WithNumber("pageSize", mcp.Description("page size"), mcp.DefaultNumber(100)),
+	)
+
+	CreateRepoLabelTool = mcp.NewTool(
+		CreateRepoLabelToolName,
+		mcp.WithDescription("Create repository label"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("label name")),
+		mcp.WithString("color", mcp.Required(), mcp.Description("label color, e.g. #00aabb")),
+		mcp.WithString("description", mcp.Description("label description")),
+	)
+
+	EditRepoLabelTool = mcp.NewTool(
+		EditRepoLabelToolName,
+		mcp.WithDescription("Edit repository label"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("label id")),
+		mcp.WithString("name", mcp.Description("label name")),
+		mcp.WithString("color", mcp.Description("label color, e.g. #00aabb")),
+		mcp.WithString("description", mcp.Description("label description")),
+	)
+
+	DeleteRepoLabelTool = mcp.NewTool(
+		DeleteRepoLabelToolName,
+		mcp.WithDescription("Delete repository label"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("label id")),
+	)
+
+	ListOrgLabelsTool = mcp.NewTool(
+		ListOrgLabelsToolName,
+		mcp.WithDescription("List organization labels, which are available to every repository of the organization"),
+		mcp.WithString("org", mcp.Required(), mcp.Description("organization name")),
+		mcp.WithNumber("page", mcp.Description("page number"), mcp.DefaultNumber(1)),
+		mcp.WithNumber("pageSize", mcp.Description("page size"), mcp.DefaultNumber(100)),
+	)
+
+	CreateOrgLabelTool = mcp.NewTool(
+		CreateOrgLabelToolName,
+		mcp.WithDescription("Create organization label"),
+		mcp.WithString("org", mcp.Required(), mcp.Description("organization name")),
+		mcp.WithString("name", mcp.Required(), mcp.Description("label name")),
+		mcp.WithString("color", mcp.Required(), mcp.Description("label color, e.g. #00aabb")),
+		mcp.WithString("description", mcp.Description("label description")),
+	)
+
+	EditOrgLabelTool = mcp.NewTool(
+		EditOrgLabelToolName,
+		mcp.WithDescription("Edit organization label"),
+		mcp.WithString("org", mcp.Required(), mcp.Description("organization name")),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("label id")),
+		mcp.WithString("name", mcp.Description("label name")),
+		mcp.WithString("color", mcp.Description("label color, e.g. #00aabb")),
+		mcp.WithString("description", mcp.Description("label description")),
+	)
+
+	DeleteOrgLabelTool = mcp.NewTool(
+		DeleteOrgLabelToolName,
+		mcp.WithDescription("Delete organization label"),
+		mcp.WithString("org", mcp.Required(), mcp.Description("organization name")),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("label id")),
+	)
+
+	AddIssueLabelsTool = mcp.NewTool(
+		AddIssueLabelsToolName,
+		mcp.WithDescription("Add labels to an issue or pull request by name"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue or pull request index")),
+		mcp.WithArray("labels", mcp.Required(), mcp.Description("label names, repository or organization labels"), labelNames),
+	)
+
+	RemoveIssueLabelsTool = mcp.NewTool(
+		RemoveIssueLabelsToolName,
+		mcp.WithDescription("Remove labels from an issue or pull request by name"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue or pull request index")),
+		mcp.WithArray("labels", mcp.Required(), mcp.Description("label names"), labelNames),
+	)
+
+	ReplaceIssueLabelsTool = mcp.NewTool(
+		ReplaceIssueLabelsToolName,
+		mcp.WithDescription("Replace all labels of an issue or pull request by name"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue or pull request index")),
+		mcp.WithArray("labels", mcp.Required(), mcp.Description("label names, repository or organization labels"), labelNames),
+	)
+
+	ClearIssueLabelsTool = mcp.NewTool(
+		ClearIssueLabelsToolName,
+		mcp.WithDescription("Remove all labels from an issue or pull request"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue or pull request index")),
+	)
+)
+
+func init() {
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListRepoLabelsTool,
+		Handler: ListRepoLabelsFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    CreateRepoLabelTool,
+		Handler: CreateRepoLabelFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    EditRepoLabelTool,
+		Handler: EditRepoLabelFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    DeleteRepoLabelTool,
+		Handler: DeleteRepoLabelFn,
+	})
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListOrgLabelsTool,
+		Handler: ListOrgLabelsFn,
+	}, tool.ReadScope(tool.ScopeOrganization))
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    CreateOrgLabelTool,
+		Handler: CreateOrgLabelFn,
+	}, tool.WriteScope(tool.ScopeOrganization))
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    EditOrgLabelTool,
+		Handler: EditOrgLabelFn,
+	}, tool.WriteScope(tool.ScopeOrganization))
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    DeleteOrgLabelTool,
+		Handler: DeleteOrgLabelFn,
+	}, tool.WriteScope(tool.ScopeOrganization))
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    AddIssueLabelsTool,
+		Handler: AddIssueLabelsFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    RemoveIssueLabelsTool,
+		Handler: RemoveIssueLabelsFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    ReplaceIssueLabelsTool,
+		Handler: ReplaceIssueLabelsFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    ClearIssueLabelsTool,
+		Handler: ClearIssueLabelsFn,
+	})
+}
+
+// labelColor accepts colors with or without the leading '#'.
+func labelColor(color string) string {
+	if color == "" || strings.HasPrefix(color, "#") {
+		return color
+	}
+	return "#" + color
+}
+
+func ListRepoLabelsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListRepoLabelsFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	page, ok := req.GetArguments()["page"].(float64)
+	if !ok {
+		page = 1
+	}
+	pageSize, ok := req.GetArguments()["pageSize"].(float64)
+	if !ok {
+		pageSize = 100
+	}
+	labels, _, err := gitea.ClientFromContext(ctx).ListRepoLabels(owner, repo, gitea_sdk.ListLabelsOptions{
+		ListOptions: gitea_sdk.ListOptions{
+			Page:     int(page),
+			PageSize: int(pageSize),
+		},
+	})
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/labels err: %v", owner, repo, err))
+	}
+	return to.TextResult(labels)
+}
+
+func CreateRepoLabelFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called CreateRepoLabelFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	name, ok := req.GetArguments()["name"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("name is required"))
+	}
+	color, ok := req.GetArguments()["color"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("color is required"))
+	}
+	description, _ := req.GetArguments()["description"].(string)
+	label, _, err := gitea.ClientFromContext(ctx).CreateLabel(owner, repo, gitea_sdk.CreateLabelOption{
+		Name:        name,
+		Color:       labelColor(color),
+		Description: description,
+	})
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("create %v/%v/label err: %v", owner, repo, err))
+	}
+	return to.TextResult(label)
+}
+
+func EditRepoLabelFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called EditRepoLabelFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	id, ok := req.GetArguments()["id"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("id is required"))
+	}
+	opt := gitea_sdk.EditLabelOption{}
+	if name, ok := req.GetArguments()["name"].(string); ok {
+		opt.Name = ptr.To(name)
+	}
+	if color, ok := req.GetArguments()["color"].(string); ok {
+		opt.Color = ptr.To(labelColor(color))
+	}
+	if description, ok := req.GetArguments()["description"].(string); ok {
+		opt.Description = ptr.To(description)
+	}
+	label, _, err := gitea.ClientFromContext(ctx).EditLabel(owner, repo, int64(id), opt)
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("edit %v/%v/labels/%v err: %v", owner, repo, int64(id), err))
+	}
+	return to.TextResult(label)
+}
+
+func DeleteRepoLabelFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called DeleteRepoLabelFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	id, ok := req.GetArguments()["id"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("id is required"))
+	}
+	_, err := gitea.ClientFromContext(ctx).DeleteLabel(owner, repo, int64(id))
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("delete %v/%v/labels/%v err: %v", owner, repo, int64(id), err))
+	}
+	return to.TextResult("Delete label success")
+}
+
+// The SDK has no organization label API, so these tools call the endpoints
+// directly.
+
+func ListOrgLabelsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListOrgLabelsFn")
+	org, ok := req.GetArguments()["org"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("org is required"))
+	}
+	page, ok := req.GetArguments()["page"].(float64)
+	if !ok {
+		page = 1
+	}
+	pageSize, ok := req.GetArguments()["pageSize"].(float64)
+	if !ok {
+		pageSize = 100
+	}
+	labels, err := listOrgLabels(ctx, org, int(page), int(pageSize))
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/labels err: %v", org, err))
+	}
+	return to.TextResult(labels)
+}
+
+func CreateOrgLabelFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called CreateOrgLabelFn")
+	org, ok := req.GetArguments()["org"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("org is required"))
+	}
+	name, ok := req.GetArguments()["name"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("name is required"))
+	}
+	color, ok := req.GetArguments()["color"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("color is required"))
+	}
+	description, _ := req.GetArguments()["description"].(string)
+	opt := gitea_sdk.CreateLabelOption{
+		Name:        name,
+		Color:       labelColor(color),
+		Description: description,
+	}
+	if err := opt.Validate(); err != nil {
+		return to.ErrorResult(err)
+	}
+	label := &gitea_sdk.Label{}
+	if err := gitea.Do(ctx, "POST", fmt.Sprintf("/orgs/%s/labels", url.PathEscape(org)), opt, label); err != nil {
+		return to.ErrorResult(fmt.Errorf("create %v/label err: %v", org, err))
+	}
+	return to.TextResult(label)
+}
+
+func EditOrgLabelFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called EditOrgLabelFn")
+	org, ok := req.GetArguments()["org"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("org is required"))
+	}
+	id, ok := req.GetArguments()["id"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("id is required"))
+	}
+	opt := gitea_sdk.EditLabelOption{}
+	if name, ok := req.GetArguments()["name"].(string); ok {
+		opt.Name = ptr.To(name)
+	}
+	if color, ok := req.GetArguments()["color"].(string); ok {
+		opt.Color = ptr.To(labelColor(color))
+	}
+	if description, ok := req.GetArguments()["description"].(string); ok {
+		opt.Description = ptr.To(description)
+	}
+	if err := opt.Validate(); err != nil {
+		return to.ErrorResult(err)
+	}
+	label := &gitea_sdk.Label{}
+	if err := gitea.Do(ctx, "PATCH", fmt.Sprintf("/orgs/%s/labels/%d", url.PathEscape(org), int64(id)), opt, label); err != nil {
+		return to.ErrorResult(fmt.Errorf("edit %v/labels/%v err: %v", org, int64(id), err))
+	}
+	return to.TextResult(label)
+}
+
+func DeleteOrgLabelFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called DeleteOrgLabelFn")
+	org, ok := req.GetArguments()["org"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("org is required"))
+	}
+	id, ok := req.GetArguments()["id"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("id is required"))
+	}
+	if err := gitea.Do(ctx, "DELETE", fmt.Sprintf("/orgs/%s/labels/%d", url.PathEscape(org), int64(id)), nil, nil); err != nil {
+		return to.ErrorResult(fmt.Errorf("delete %v/labels/%v err: %v", org, int64(id), err))
+	}
+	return to.TextResult("Delete label success")
+}
+
+func listOrgLabels(ctx context.Context, org string, page, pageSize int) ([]*gitea_sdk.Label, error) {
+	var labels []*gitea_sdk.Label
+	path := fmt.Sprintf("/orgs/%s/labels?page=%d&limit=%d", url.PathEscape(org), page, pageSize)
+	if err := gitea.Do(ctx, "GET", path, nil, &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// resolveLabelIDs maps label names, case-insensitively, to the IDs of the
+// repository's labels and, when the owner is an organization, its
+// organization labels. Repository labels win over organization labels of
+// the same name.
+func resolveLabelIDs(ctx context.Context, owner, repo string, names []string) ([]int64, error) {
+	ids := make(map[string]int64)
+	const pageSize = 50
+	for page := 1; ; page++ {
+		labels, err := listOrgLabels(ctx, owner, page, pageSize)
+		if gitea.IsNotFound(err) {
+			// not an organization
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get %v/labels err: %v", owner, err)
+		}
+		for _, l := range labels {
+			ids[strings.ToLower(l.Name)] = l.ID
+		}
+		if len(labels) < pageSize {
+			break
+		}
+	}
+	for page := 1; ; page++ {
+		labels, _, err := gitea.ClientFromContext(ctx).ListRepoLabels(owner, repo, gitea_sdk.ListLabelsOptions{
+			ListOptions: gitea_sdk.ListOptions{Page: page, PageSize: pageSize},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("get %v/%v/labels err: %v", owner, repo, err)
+		}
+		for _, l := range labels {
+			ids[strings.ToLower(l.Name)] = l.ID
+		}
+		if len(labels) < pageSize {
+			break
+		}
+	}
+
+	resolved := make([]int64, 0, len(names))
+	var unknown []string
+	for _, name := range names {
+		id, ok := ids[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		resolved = append(resolved, id)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown labels in %v/%v: %s", owner, repo, strings.Join(unknown, ", "))
+	}
+	return resolved, nil
+}
+
+// issueLabelArgs parses the owner, repo, index and, if withLabels, the label
+// names shared by the issue label tools, and resolves the names to IDs.
+func issueLabelArgs(ctx context.Context, req mcp.CallToolRequest, withLabels bool) (owner, repo string, index int64, ids []int64, err error) {
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return "", "", 0, nil, fmt.Errorf("owner is required")
+	}
+	repo, ok = req.GetArguments()["repo"].(string)
+	if !ok {
+		return "", "", 0, nil, fmt.Errorf("repo is required")
+	}
+	i, ok := req.GetArguments()["index"].(float64)
+	if !ok {
+		return "", "", 0, nil, fmt.Errorf("index is required")
+	}
+	if !withLabels {
+		return owner, repo, int64(i), nil, nil
+	}
+	names := to.Strings(req.GetArguments()["labels"])
+	if len(names) == 0 {
+		return "", "", 0, nil, fmt.Errorf("labels is required")
+	}
+	ids, err = resolveLabelIDs(ctx, owner, repo, names)
+	return owner, repo, int64(i), ids, err
+}
+
+func AddIssueLabelsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called AddIssueLabelsFn")
+	owner, repo, index, ids, err := issueLabelArgs(ctx, req, true)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	labels, _, err := gitea.ClientFromContext(ctx).AddIssueLabels(owner, repo, index, gitea_sdk.IssueLabelsOption{Labels: ids})
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("add %v/%v/issues/%v/labels err: %v", owner, repo, index, err))
+	}
+	return to.TextResult(labels)
+}
+
+func RemoveIssueLabelsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called RemoveIssueLabelsFn")
+	owner, repo, index, ids, err := issueLabelArgs(ctx, req, true)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	client := gitea.ClientFromContext(ctx)
+	for _, id := range ids {
+		if _, err := client.DeleteIssueLabel(owner, repo, index, id); err != nil {
+			return to.ErrorResult(fmt.Errorf("delete %v/%v/issues/%v/labels/%v err: %v", owner, repo, index, id, err))
+		}
+	}
+	labels, _, err := client.GetIssueLabels(owner, repo, index, gitea_sdk.ListLabelsOptions{})
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/issues/%v/labels err: %v", owner, repo, index, err))
+	}
+	return to.TextResult(labels)
+}
+
+func ReplaceIssueLabelsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ReplaceIssueLabelsFn")
+	owner, repo, index, ids, err := issueLabelArgs(ctx, req, true)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	labels, _, err := gitea.ClientFromContext(ctx).ReplaceIssueLabels(owner, repo, index, gitea_sdk.IssueLabelsOption{Labels: ids})
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("replace %v/%v/issues/%v/labels err: %v", owner, repo, index, err))
+	}
+	return to.TextResult(labels)
+}
+
+func ClearIssueLabelsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ClearIssueLabelsFn")
+	owner, repo, index, _, err := issueLabelArgs(ctx, req, false)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	if _, err := gitea.ClientFromContext(ctx).ClearIssueLabels(owner, repo, index); err != nil {
+		return to.ErrorResult(fmt.Errorf("clear %v/%v/issues/%v/labels err: %v", owner, repo, index, err))
+	}
+	return to.TextResult("Clear labels success")
+}
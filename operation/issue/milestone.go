@@ -0,0 +1,261 @@
+package issue
+
+import (
+	"context"
+	"fmt"
+
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/ptr"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	ListRepoMilestonesToolName = "list_repo_milestones"
+	GetMilestoneToolName       = "get_milestone"
+	CreateMilestoneToolName    = "create_milestone"
+	EditMilestoneToolName      = "edit_milestone"
+	DeleteMilestoneToolName    = "delete_milestone"
+)
+
+var (
+	ListRepoMilestonesTool = mcp.NewTool(
+		ListRepoMilestonesToolName,
+		mcp.WithDescription("List repository milestones with their state, due date and open/closed issue counts"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithString("state", mcp.Description("milestone state"), mcp.Enum("open", "closed", "all"), mcp.DefaultString("all")),
+		mcp.WithString("name", mcp.Description("filter by milestone title")),
+		mcp.WithNumber("page", mcp.Description("page number"), mcp.DefaultNumber(1)),
+		mcp.WithNumber("pageSize", mcp.Description("page size"), mcp.DefaultNumber(100)),
+	)
+
+	GetMilestoneTool = mcp.NewTool(
+		GetMilestoneToolName,
+		mcp.WithDescription("Get milestone by ID or title"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithString("milestone", mcp.Required(), mcp.Description("milestone ID or title")),
+	)
+
+	CreateMilestoneTool = mcp.NewTool(
+		CreateMilestoneToolName,
+		mcp.WithDescription("Create milestone"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithString("title", mcp.Required(), mcp.Description("milestone title")),
+		mcp.WithString("description", mcp.Description("milestone description")),
+		mcp.WithString("due_date", mcp.Description("due date, YYYY-MM-DD or RFC 3339")),
+		mcp.WithString("state", mcp.Description("milestone state"), mcp.Enum("open", "closed"), mcp.DefaultString("open")),
+	)
+
+	EditMilestoneTool = mcp.NewTool(
+		EditMilestoneToolName,
+		mcp.WithDescription("Edit milestone, e.g. close it or move its due date"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithString("milestone", mcp.Required(), mcp.Description("milestone ID or title")),
+		mcp.WithString("title", mcp.Description("new milestone title")),
+		mcp.WithString("description", mcp.Description("milestone description")),
+		mcp.WithString("due_date", mcp.Description("due date, YYYY-MM-DD or RFC 3339")),
+		mcp.WithString("state", mcp.Description("milestone state"), mcp.Enum("open", "closed")),
+	)
+
+	DeleteMilestoneTool = mcp.NewTool(
+		DeleteMilestoneToolName,
+		mcp.WithDescription("Delete milestone"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithString("milestone", mcp.Required(), mcp.Description("milestone ID or title")),
+	)
+)
+
+func init() {
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListRepoMilestonesTool,
+		Handler: ListRepoMilestonesFn,
+	})
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    GetMilestoneTool,
+		Handler: GetMilestoneFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    CreateMilestoneTool,
+		Handler: CreateMilestoneFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    EditMilestoneTool,
+		Handler: EditMilestoneFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    DeleteMilestoneTool,
+		Handler: DeleteMilestoneFn,
+	})
+}
+
+func ListRepoMilestonesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListRepoMilestonesFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	state, ok := req.GetArguments()["state"].(string)
+	if !ok {
+		state = "all"
+	}
+	name, _ := req.GetArguments()["name"].(string)
+	page, ok := req.GetArguments()["page"].(float64)
+	if !ok {
+		page = 1
+	}
+	pageSize, ok := req.GetArguments()["pageSize"].(float64)
+	if !ok {
+		pageSize = 100
+	}
+	milestones, _, err := gitea.ClientFromContext(ctx).ListRepoMilestones(owner, repo, gitea_sdk.ListMilestoneOption{
+		ListOptions: gitea_sdk.ListOptions{
+			Page:     int(page),
+			PageSize: int(pageSize),
+		},
+		State: gitea_sdk.StateType(state),
+		Name:  name,
+	})
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/milestones err: %v", owner, repo, err))
+	}
+	return to.TextResult(milestones)
+}
+
+func GetMilestoneFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called GetMilestoneFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	id, err := gitea.ResolveMilestoneID(ctx, owner, repo, req.GetArguments()["milestone"])
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	if id == 0 {
+		return to.ErrorResult(fmt.Errorf("milestone is required"))
+	}
+	milestone, _, err := gitea.ClientFromContext(ctx).GetMilestone(owner, repo, id)
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/milestones/%v err: %v", owner, repo, id, err))
+	}
+	return to.TextResult(milestone)
+}
+
+func CreateMilestoneFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called CreateMilestoneFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	title, ok := req.GetArguments()["title"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("title is required"))
+	}
+	description, _ := req.GetArguments()["description"].(string)
+	state, ok := req.GetArguments()["state"].(string)
+	if !ok {
+		state = "open"
+	}
+	opt := gitea_sdk.CreateMilestoneOption{
+		Title:       title,
+		Description: description,
+		State:       gitea_sdk.StateType(state),
+	}
+	if dueDate, ok := req.GetArguments()["due_date"].(string); ok && dueDate != "" {
+		deadline, err := to.Time(dueDate)
+		if err != nil {
+			return to.ErrorResult(err)
+		}
+		opt.Deadline = &deadline
+	}
+	milestone, _, err := gitea.ClientFromContext(ctx).CreateMilestone(owner, repo, opt)
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("create %v/%v/milestone err: %v", owner, repo, err))
+	}
+	return to.TextResult(milestone)
+}
+
+func EditMilestoneFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called EditMilestoneFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	id, err := gitea.ResolveMilestoneID(ctx, owner, repo, req.GetArguments()["milestone"])
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	if id == 0 {
+		return to.ErrorResult(fmt.Errorf("milestone is required"))
+	}
+	opt := gitea_sdk.EditMilestoneOption{}
+	if title, ok := req.GetArguments()["title"].(string); ok {
+		opt.Title = title
+	}
+	if description, ok := req.GetArguments()["description"].(string); ok {
+		opt.Description = ptr.To(description)
+	}
+	if state, ok := req.GetArguments()["state"].(string); ok {
+		opt.State = ptr.To(gitea_sdk.StateType(state))
+	}
+	if dueDate, ok := req.GetArguments()["due_date"].(string); ok && dueDate != "" {
+		deadline, err := to.Time(dueDate)
+		if err != nil {
+			return to.ErrorResult(err)
+		}
+		opt.Deadline = &deadline
+	}
+	milestone, _, err := gitea.ClientFromContext(ctx).EditMilestone(owner, repo, id, opt)
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("edit %v/%v/milestones/%v err: %v", owner, repo, id, err))
+	}
+	return to.TextResult(milestone)
+}
+
+func DeleteMilestoneFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called DeleteMilestoneFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	id, err := gitea.ResolveMilestoneID(ctx, owner, repo, req.GetArguments()["milestone"])
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	if id == 0 {
+		return to.ErrorResult(fmt.Errorf("milestone is required"))
+	}
+	if _, err := gitea.ClientFromContext(ctx).DeleteMilestone(owner, repo, id); err != nil {
+		return to.ErrorResult(fmt.Errorf("delete %v/%v/milestones/%v err: %v", owner, repo, id, err))
+	}
+	return to.TextResult("Delete milestone success")
+}
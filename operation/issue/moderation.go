@@ -0,0 +1,187 @@
+package issue
+
+import (
+	"context"
+	"fmt"
+
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	DeleteIssueCommentToolName = "delete_issue_comment"
+	DeleteIssueToolName        = "delete_issue"
+	LockIssueToolName          = "lock_issue"
+	UnlockIssueToolName        = "unlock_issue"
+	PinIssueToolName           = "pin_issue"
+	UnpinIssueToolName         = "unpin_issue"
+)
+
+var (
+	DeleteIssueCommentTool = mcp.NewTool(
+		DeleteIssueCommentToolName,
+		mcp.WithDescription("Delete an issue comment"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("commentID", mcp.Required(), mcp.Description("id of issue comment")),
+	)
+
+	DeleteIssueTool = mcp.NewTool(
+		DeleteIssueToolName,
+		mcp.WithDescription("Delete an issue or pull request permanently. Requires admin rights on the repository"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+	)
+
+	LockIssueTool = mcp.NewTool(
+		LockIssueToolName,
+		mcp.WithDescription("Lock the conversation of an issue or pull request so only collaborators can comment"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithString("reason", mcp.Description("lock reason"), mcp.Enum("Too heated", "Off-topic", "Resolved", "Spam")),
+	)
+
+	UnlockIssueTool = mcp.NewTool(
+		UnlockIssueToolName,
+		mcp.WithDescription("Unlock the conversation of an issue or pull request"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+	)
+
+	PinIssueTool = mcp.NewTool(
+		PinIssueToolName,
+		mcp.WithDescription("Pin an issue or pull request to the top of the repository's list"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+	)
+
+	UnpinIssueTool = mcp.NewTool(
+		UnpinIssueToolName,
+		mcp.WithDescription("Unpin an issue or pull request"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+	)
+)
+
+func init() {
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    DeleteIssueCommentTool,
+		Handler: DeleteIssueCommentFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    DeleteIssueTool,
+		Handler: DeleteIssueFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    LockIssueTool,
+		Handler: LockIssueFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    UnlockIssueTool,
+		Handler: UnlockIssueFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    PinIssueTool,
+		Handler: PinIssueFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    UnpinIssueTool,
+		Handler: UnpinIssueFn,
+	})
+	// locking has an API since Gitea 1.23, pinning since 1.21
+	Tool.RequireVersion(LockIssueToolName, "1.23.0")
+	Tool.RequireVersion(UnlockIssueToolName, "1.23.0")
+	Tool.RequireVersion(PinIssueToolName, "1.21.0")
+	Tool.RequireVersion(UnpinIssueToolName, "1.21.0")
+}
+
+func DeleteIssueCommentFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called DeleteIssueCommentFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	commentID, ok := req.GetArguments()["commentID"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("commentID is required"))
+	}
+	if _, err := gitea.ClientFromContext(ctx).DeleteIssueComment(owner, repo, int64(commentID)); err != nil {
+		return to.ErrorResult(fmt.Errorf("delete %v/%v/issues/comments/%v err: %v", owner, repo, int64(commentID), err))
+	}
+	return to.TextResult("Delete comment success")
+}
+
+func DeleteIssueFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called DeleteIssueFn")
+	owner, repo, index, err := issueArgs(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	if _, err := gitea.ClientFromContext(ctx).DeleteIssue(owner, repo, index); err != nil {
+		return to.ErrorResult(fmt.Errorf("delete %v/%v/issues/%v err: %v", owner, repo, index, err))
+	}
+	return to.TextResult("Delete issue success")
+}
+
+func LockIssueFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called LockIssueFn")
+	owner, repo, index, err := issueArgs(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	reason, _ := req.GetArguments()["reason"].(string)
+	opt := map[string]string{"lock_reason": reason}
+	if err := gitea.Do(ctx, "PUT", issuePath(owner, repo, index, "lock"), opt, nil); err != nil {
+		return to.ErrorResult(fmt.Errorf("lock %v/%v/issues/%v err: %v", owner, repo, index, err))
+	}
+	return to.TextResult("Lock issue success")
+}
+
+func UnlockIssueFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called UnlockIssueFn")
+	owner, repo, index, err := issueArgs(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	if err := gitea.Do(ctx, "DELETE", issuePath(owner, repo, index, "lock"), nil, nil); err != nil {
+		return to.ErrorResult(fmt.Errorf("unlock %v/%v/issues/%v err: %v", owner, repo, index, err))
+	}
+	return to.TextResult("Unlock issue success")
+}
+
+func PinIssueFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called PinIssueFn")
+	owner, repo, index, err := issueArgs(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	if err := gitea.Do(ctx, "POST", issuePath(owner, repo, index, "pin"), nil, nil); err != nil {
+		return to.ErrorResult(fmt.Errorf("pin %v/%v/issues/%v err: %v", owner, repo, index, err))
+	}
+	return to.TextResult("Pin issue success")
+}
+
+func UnpinIssueFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called UnpinIssueFn")
+	owner, repo, index, err := issueArgs(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	if err := gitea.Do(ctx, "DELETE", issuePath(owner, repo, index, "pin"), nil, nil); err != nil {
+		return to.ErrorResult(fmt.Errorf("unpin %v/%v/issues/%v err: %v", owner, repo, index, err))
+	}
+	return to.TextResult("Unpin issue success")
+}
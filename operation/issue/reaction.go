@@ -0,0 +1,245 @@
+package issue
+
+import (
+	"context"
+	"fmt"
+
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	ListIssueReactionsToolName         = "list_issue_reactions"
+	AddIssueReactionToolName           = "add_issue_reaction"
+	RemoveIssueReactionToolName        = "remove_issue_reaction"
+	ListIssueCommentReactionsToolName  = "list_issue_comment_reactions"
+	AddIssueCommentReactionToolName    = "add_issue_comment_reaction"
+	RemoveIssueCommentReactionToolName = "remove_issue_comment_reaction"
+)
+
+const reactionDescription = "reaction, e.g. +1, -1, laugh, hooray, confused, heart, rocket or eyes"
+
+var (
+	ListIssueReactionsTool = mcp.NewTool(
+		ListIssueReactionsToolName,
+		mcp.WithDescription("List reactions on an issue or pull request"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+	)
+
+	AddIssueReactionTool = mcp.NewTool(
+		AddIssueReactionToolName,
+		mcp.WithDescription("Add a reaction to an issue or pull request"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithString("reaction", mcp.Required(), mcp.Description(reactionDescription)),
+	)
+
+	RemoveIssueReactionTool = mcp.NewTool(
+		RemoveIssueReactionToolName,
+		mcp.WithDescription("Remove your reaction from an issue or pull request"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithString("reaction", mcp.Required(), mcp.Description(reactionDescription)),
+	)
+
+	ListIssueCommentReactionsTool = mcp.NewTool(
+		ListIssueCommentReactionsToolName,
+		mcp.WithDescription("List reactions on an issue comment"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("commentID", mcp.Required(), mcp.Description("id of issue comment")),
+	)
+
+	AddIssueCommentReactionTool = mcp.NewTool(
+		AddIssueCommentReactionToolName,
+		mcp.WithDescription("Add a reaction to an issue comment"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("commentID", mcp.Required(), mcp.Description("id of issue comment")),
+		mcp.WithString("reaction", mcp.Required(), mcp.Description(reactionDescription)),
+	)
+
+	RemoveIssueCommentReactionTool = mcp.NewTool(
+		RemoveIssueCommentReactionToolName,
+		mcp.WithDescription("Remove your reaction from an issue comment"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("commentID", mcp.Required(), mcp.Description("id of issue comment")),
+		mcp.WithString("reaction", mcp.Required(), mcp.Description(reactionDescription)),
+	)
+)
+
+func init() {
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListIssueReactionsTool,
+		Handler: ListIssueReactionsFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    AddIssueReactionTool,
+		Handler: AddIssueReactionFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    RemoveIssueReactionTool,
+		Handler: RemoveIssueReactionFn,
+	})
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListIssueCommentReactionsTool,
+		Handler: ListIssueCommentReactionsFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    AddIssueCommentReactionTool,
+		Handler: AddIssueCommentReactionFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    RemoveIssueCommentReactionTool,
+		Handler: RemoveIssueCommentReactionFn,
+	})
+}
+
+func ListIssueReactionsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListIssueReactionsFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	index, ok := req.GetArguments()["index"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("index is required"))
+	}
+	reactions, _, err := gitea.ClientFromContext(ctx).GetIssueReactions(owner, repo, int64(index))
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/issues/%v/reactions err: %v", owner, repo, int64(index), err))
+	}
+	return to.TextResult(reactions)
+}
+
+func AddIssueReactionFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called AddIssueReactionFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	index, ok := req.GetArguments()["index"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("index is required"))
+	}
+	reaction, ok := req.GetArguments()["reaction"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("reaction is required"))
+	}
+	r, _, err := gitea.ClientFromContext(ctx).PostIssueReaction(owner, repo, int64(index), reaction)
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("add %v/%v/issues/%v/reactions err: %v", owner, repo, int64(index), err))
+	}
+	return to.TextResult(r)
+}
+
+func RemoveIssueReactionFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called RemoveIssueReactionFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	index, ok := req.GetArguments()["index"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("index is required"))
+	}
+	reaction, ok := req.GetArguments()["reaction"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("reaction is required"))
+	}
+	if _, err := gitea.ClientFromContext(ctx).DeleteIssueReaction(owner, repo, int64(index), reaction); err != nil {
+		return to.ErrorResult(fmt.Errorf("delete %v/%v/issues/%v/reactions err: %v", owner, repo, int64(index), err))
+	}
+	return to.TextResult("Remove reaction success")
+}
+
+func ListIssueCommentReactionsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListIssueCommentReactionsFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	commentID, ok := req.GetArguments()["commentID"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("comment ID is required"))
+	}
+	reactions, _, err := gitea.ClientFromContext(ctx).GetIssueCommentReactions(owner, repo, int64(commentID))
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/issues/comments/%v/reactions err: %v", owner, repo, int64(commentID), err))
+	}
+	return to.TextResult(reactions)
+}
+
+func AddIssueCommentReactionFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called AddIssueCommentReactionFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	commentID, ok := req.GetArguments()["commentID"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("comment ID is required"))
+	}
+	reaction, ok := req.GetArguments()["reaction"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("reaction is required"))
+	}
+	r, _, err := gitea.ClientFromContext(ctx).PostIssueCommentReaction(owner, repo, int64(commentID), reaction)
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("add %v/%v/issues/comments/%v/reactions err: %v", owner, repo, int64(commentID), err))
+	}
+	return to.TextResult(r)
+}
+
+func RemoveIssueCommentReactionFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called RemoveIssueCommentReactionFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	commentID, ok := req.GetArguments()["commentID"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("comment ID is required"))
+	}
+	reaction, ok := req.GetArguments()["reaction"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("reaction is required"))
+	}
+	if _, err := gitea.ClientFromContext(ctx).DeleteIssueCommentReaction(owner, repo, int64(commentID), reaction); err != nil {
+		return to.ErrorResult(fmt.Errorf("delete %v/%v/issues/comments/%v/reactions err: %v", owner, repo, int64(commentID), err))
+	}
+	return to.TextResult("Remove reaction success")
+}
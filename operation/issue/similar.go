@@ -0,0 +1,397 @@
+package issue
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	FindSimilarIssuesToolName = "find_similar_issues"
+)
+
+const (
+	// similarCacheTTL is how long a repository's issues are used before the
+	// issues updated since are fetched.
+	similarCacheTTL = 5 * time.Minute
+	// similarCacheRebuild is how long incremental fetches are used before
+	// all issues are fetched again, dropping deleted and transferred ones.
+	similarCacheRebuild = time.Hour
+	// similarCacheRepos bounds the repositories kept in the cache.
+	similarCacheRepos = 32
+	// maxSimilarIssues bounds the issues a full fetch indexes per repository.
+	maxSimilarIssues = 5000
+	// maxBodyTokens bounds the tokens indexed from an issue body.
+	maxBodyTokens = 2000
+
+	// BM25 parameters
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// field weights, applied by counting a term that many times
+const (
+	titleWeight = 3
+	labelWeight = 2
+	bodyWeight  = 1
+)
+
+var FindSimilarIssuesTool = mcp.NewTool(
+	FindSimilarIssuesToolName,
+	mcp.WithDescription("Find existing issues similar to a candidate title and body, e.g. to avoid filing a duplicate. Ranks the repository's issues with BM25 over titles, bodies and labels; issues are cached and refreshed incrementally"),
+	mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+	mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+	mcp.WithString("title", mcp.Required(), mcp.Description("candidate issue title")),
+	mcp.WithString("body", mcp.Description("candidate issue body")),
+	mcp.WithString("state", mcp.Description("only match issues in this state"), mcp.Enum("open", "closed", "all"), mcp.DefaultString("all")),
+	mcp.WithString("type", mcp.Description("whether to match issues, pull requests or both"), mcp.Enum("issues", "pulls", "all"), mcp.DefaultString("issues")),
+	mcp.WithNumber("exclude", mcp.Description("index of an issue to leave out, such as the candidate itself")),
+	mcp.WithNumber("limit", mcp.Description("number of matches to return"), mcp.DefaultNumber(5)),
+	mcp.WithBoolean("refresh", mcp.Description("fetch all issues again instead of using the cache"), mcp.DefaultBool(false)),
+)
+
+func init() {
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    FindSimilarIssuesTool,
+		Handler: FindSimilarIssuesFn,
+	})
+}
+
+// issueDoc is an issue indexed for similarity ranking.
+type issueDoc struct {
+	index   int64
+	title   string
+	state   string
+	labels  []string
+	htmlURL string
+	isPull  bool
+	updated time.Time
+	terms   map[string]int
+	length  int
+}
+
+// issueCorpus holds the indexed issues of a repository.
+type issueCorpus struct {
+	mu        sync.Mutex
+	docs      map[int64]*issueDoc
+	fetchedAt time.Time
+	builtAt   time.Time
+	usedAt    time.Time
+}
+
+var (
+	similarCache   = map[string]*issueCorpus{}
+	similarCacheMu sync.Mutex
+)
+
+// corpusFor returns the cache entry of a repository as seen with the token
+// and sudo user of ctx, since they decide which issues are visible.
+func corpusFor(ctx context.Context, owner, repo string) *issueCorpus {
+	key := strings.Join([]string{gitea.Token(ctx), gitea.Sudo(ctx), strings.ToLower(owner), strings.ToLower(repo)}, "\x00")
+	similarCacheMu.Lock()
+	defer similarCacheMu.Unlock()
+	c, ok := similarCache[key]
+	if !ok {
+		if len(similarCache) >= similarCacheRepos {
+			// evict the least recently used repository
+			var oldest string
+			for k, v := range similarCache {
+				if oldest == "" || v.usedAt.Before(similarCache[oldest].usedAt) {
+					oldest = k
+				}
+			}
+			delete(similarCache, oldest)
+		}
+		c = &issueCorpus{}
+		similarCache[key] = c
+	}
+	c.usedAt = time.Now()
+	return c
+}
+
+// refresh fetches the issues updated since the last fetch, or all issues
+// when the cache is empty, older than similarCacheRebuild or refetch is set.
+func (c *issueCorpus) refresh(ctx context.Context, owner, repo string, refetch bool) error {
+	if !refetch && c.docs != nil && time.Since(c.fetchedAt) < similarCacheTTL {
+		return nil
+	}
+	full := refetch || c.docs == nil || time.Since(c.builtAt) >= similarCacheRebuild
+	docs := c.docs
+	opt := gitea_sdk.ListIssueOption{State: gitea_sdk.StateAll}
+	if full {
+		docs = map[int64]*issueDoc{}
+	} else {
+		// allow for clock skew between this host and Gitea
+		opt.Since = c.fetchedAt.Add(-time.Minute)
+	}
+	started := time.Now()
+	const pageSize = 50
+	for page := 1; ; page++ {
+		opt.ListOptions = gitea_sdk.ListOptions{Page: page, PageSize: pageSize}
+		issues, _, err := gitea.ClientFromContext(ctx).ListRepoIssues(owner, repo, opt)
+		if err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			docs[issue.Index] = newIssueDoc(issue)
+		}
+		// only a full fetch is capped, an incremental one has to catch up
+		// with every update
+		if len(issues) < pageSize || full && len(docs) >= maxSimilarIssues {
+			break
+		}
+	}
+	c.docs = docs
+	c.fetchedAt = started
+	if full {
+		c.builtAt = started
+	}
+	return nil
+}
+
+func newIssueDoc(issue *gitea_sdk.Issue) *issueDoc {
+	doc := &issueDoc{
+		index:   issue.Index,
+		title:   issue.Title,
+		state:   string(issue.State),
+		htmlURL: issue.HTMLURL,
+		isPull:  issue.PullRequest != nil,
+		updated: issue.Updated,
+		labels:  []string{},
+		terms:   map[string]int{},
+	}
+	add := func(text string, weight, max int) {
+		tokens := tokenize(text)
+		if max > 0 && len(tokens) > max {
+			tokens = tokens[:max]
+		}
+		for _, t := range tokens {
+			doc.terms[t] += weight
+			doc.length += weight
+		}
+	}
+	add(issue.Title, titleWeight, 0)
+	add(issue.Body, bodyWeight, maxBodyTokens)
+	for _, l := range issue.Labels {
+		doc.labels = append(doc.labels, l.Name)
+		add(l.Name, labelWeight, 0)
+	}
+	return doc
+}
+
+var stopWords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`a an and are as at be been but by can could did do does for from had has have
+		how i if in into is it its me my no not of on or our should so that the their them then there these they
+		this to was we were what when where which while who why will with would you your`) {
+		stopWords[w] = true
+	}
+}
+
+// tokenize splits text into lower case words and numbers, dropping stop
+// words and reducing common English suffixes so that, for example, "crash",
+// "crashes" and "crashing" match.
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make([]string, 0, len(words))
+	for _, w := range words {
+		if len(w) < 2 || stopWords[w] {
+			continue
+		}
+		tokens = append(tokens, stem(w))
+	}
+	return tokens
+}
+
+func stem(w string) string {
+	switch {
+	case strings.HasSuffix(w, "ing") && len(w) >= 6:
+		return strings.TrimSuffix(w, "ing")
+	case strings.HasSuffix(w, "ed") && len(w) >= 5:
+		return strings.TrimSuffix(w, "ed")
+	case strings.HasSuffix(w, "es") && len(w) >= 5 && hasSibilantEnd(strings.TrimSuffix(w, "es")):
+		// "crashes", "fixes", but not "files"
+		return strings.TrimSuffix(w, "es")
+	case strings.HasSuffix(w, "s") && len(w) >= 4 &&
+		!strings.HasSuffix(w, "ss") && !strings.HasSuffix(w, "us") && !strings.HasSuffix(w, "is"):
+		return strings.TrimSuffix(w, "s")
+	}
+	return w
+}
+
+func hasSibilantEnd(w string) bool {
+	for _, end := range []string{"s", "x", "z", "ch", "sh"} {
+		if strings.HasSuffix(w, end) {
+			return true
+		}
+	}
+	return false
+}
+
+// SimilarIssue is a match of find_similar_issues.
+type SimilarIssue struct {
+	Index        int64     `json:"index"`
+	Title        string    `json:"title"`
+	State        string    `json:"state"`
+	PullRequest  bool      `json:"pull_request"`
+	Labels       []string  `json:"labels"`
+	HTMLURL      string    `json:"html_url"`
+	Updated      time.Time `json:"updated_at"`
+	Score        float64   `json:"score"`
+	MatchedTerms []string  `json:"matched_terms"`
+}
+
+// SimilarIssues is the result of find_similar_issues.
+type SimilarIssues struct {
+	Indexed   int             `json:"indexed"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Matches   []*SimilarIssue `json:"matches"`
+}
+
+// rank scores the docs against the query terms with BM25. Document
+// frequencies are taken over all docs, not just those matching the filters.
+func rank(docs []*issueDoc, all map[int64]*issueDoc, query []string, limit int) []*SimilarIssue {
+	if len(all) == 0 {
+		return []*SimilarIssue{}
+	}
+	queryTerms := map[string]int{}
+	for _, t := range query {
+		queryTerms[t]++
+	}
+	var totalLength int
+	df := map[string]int{}
+	for _, d := range all {
+		totalLength += d.length
+		for t := range queryTerms {
+			if d.terms[t] > 0 {
+				df[t]++
+			}
+		}
+	}
+	avgLength := math.Max(float64(totalLength)/float64(len(all)), 1)
+	n := float64(len(all))
+
+	matches := []*SimilarIssue{}
+	for _, d := range docs {
+		var score float64
+		var matched []string
+		for t, qf := range queryTerms {
+			tf := float64(d.terms[t])
+			if tf == 0 {
+				continue
+			}
+			idf := math.Log(1 + (n-float64(df[t])+0.5)/(float64(df[t])+0.5))
+			score += float64(qf) * idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(d.length)/avgLength))
+			matched = append(matched, t)
+		}
+		if score == 0 {
+			continue
+		}
+		sort.Strings(matched)
+		matches = append(matches, &SimilarIssue{
+			Index:        d.index,
+			Title:        d.title,
+			State:        d.state,
+			PullRequest:  d.isPull,
+			Labels:       d.labels,
+			HTMLURL:      d.htmlURL,
+			Updated:      d.updated,
+			Score:        math.Round(score*1000) / 1000,
+			MatchedTerms: matched,
+		})
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].Index > matches[j].Index
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+func FindSimilarIssuesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called FindSimilarIssuesFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	title, ok := req.GetArguments()["title"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("title is required"))
+	}
+	body, _ := req.GetArguments()["body"].(string)
+	state, ok := req.GetArguments()["state"].(string)
+	if !ok {
+		state = "all"
+	}
+	issueType, ok := req.GetArguments()["type"].(string)
+	if !ok {
+		issueType = "issues"
+	}
+	exclude, _ := req.GetArguments()["exclude"].(float64)
+	limit, ok := req.GetArguments()["limit"].(float64)
+	if !ok || limit < 1 {
+		limit = 5
+	}
+	refetch, _ := req.GetArguments()["refresh"].(bool)
+
+	// the title counts more than the body, like in the indexed issues
+	var query []string
+	for range titleWeight {
+		query = append(query, tokenize(title)...)
+	}
+	bodyTokens := tokenize(body)
+	if len(bodyTokens) > maxBodyTokens {
+		bodyTokens = bodyTokens[:maxBodyTokens]
+	}
+	query = append(query, bodyTokens...)
+	if len(query) == 0 {
+		return to.ErrorResult(fmt.Errorf("title and body have no searchable words"))
+	}
+
+	corpus := corpusFor(ctx, owner, repo)
+	corpus.mu.Lock()
+	defer corpus.mu.Unlock()
+	if err := corpus.refresh(ctx, owner, repo, refetch); err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/issues err: %v", owner, repo, err))
+	}
+
+	docs := make([]*issueDoc, 0, len(corpus.docs))
+	for _, d := range corpus.docs {
+		switch {
+		case d.index == int64(exclude):
+		case state != "all" && d.state != state:
+		case issueType == "issues" && d.isPull, issueType == "pulls" && !d.isPull:
+		default:
+			docs = append(docs, d)
+		}
+	}
+	return to.TextResult(SimilarIssues{
+		Indexed:   len(corpus.docs),
+		FetchedAt: corpus.fetchedAt,
+		Matches:   rank(docs, corpus.docs, query, int(limit)),
+	})
+}
@@ -0,0 +1,109 @@
+package issue
+
+import (
+	"slices"
+	"testing"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"The server crashes when uploading", []string{"server", "crash", "upload"}},
+		{"Crash: crashed, crashing!", []string{"crash", "crash", "crash"}},
+		{"Error 500 in API v2", []string{"error", "500", "api", "v2"}},
+		{"a b c x", []string{}},
+		{"class access pass", []string{"class", "access", "pass"}},
+		{"Über größe", []string{"über", "größe"}},
+	}
+	for _, tt := range tests {
+		if got := tokenize(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		word, want string
+	}{
+		{"uploading", "upload"},
+		{"uploaded", "upload"},
+		{"crashes", "crash"},
+		{"files", "file"},
+		{"is", "is"},
+		{"bus", "bus"},
+		{"red", "red"},
+		{"sing", "sing"},
+		{"address", "address"},
+		{"fixes", "fix"},
+		{"uses", "use"},
+		{"status", "status"},
+		{"analysis", "analysis"},
+	}
+	for _, tt := range tests {
+		if got := stem(tt.word); got != tt.want {
+			t.Errorf("stem(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	all := map[int64]*issueDoc{}
+	for _, issue := range []*gitea_sdk.Issue{
+		{Index: 1, Title: "Crash when uploading large attachments", Body: "The server panics on attachments over 100MB", Labels: []*gitea_sdk.Label{{Name: "bug"}}},
+		{Index: 2, Title: "Add dark theme", Body: "Please add a dark theme"},
+		{Index: 3, Title: "Upload fails for big files", Body: "Uploading files larger than 50MB fails"},
+		{Index: 4, Title: "Dark theme crash", Body: "Switching the theme crashes the page"},
+	} {
+		all[issue.Index] = newIssueDoc(issue)
+	}
+	docs := make([]*issueDoc, 0, len(all))
+	for _, d := range all {
+		docs = append(docs, d)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []int64
+	}{
+		{"best match first", "crash uploading attachment", 5, []int64{1, 4, 3}},
+		{"title words", "dark theme", 5, []int64{2, 4}},
+		{"limit", "crash uploading attachment", 1, []int64{1}},
+		{"no match", "kubernetes", 5, []int64{}},
+		{"labels count", "bug", 5, []int64{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := rank(docs, all, tokenize(tt.query), tt.limit)
+			got := []int64{}
+			for _, m := range matches {
+				got = append(got, m.Index)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("rank(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+			for i := 1; i < len(matches); i++ {
+				if matches[i].Score > matches[i-1].Score {
+					t.Errorf("matches not sorted by score: %v", matches)
+				}
+			}
+		})
+	}
+
+	// document frequencies come from every indexed issue, so a filtered
+	// selection scores the same as in the whole repository
+	filtered := rank([]*issueDoc{all[3]}, all, tokenize("upload"), 5)
+	unfiltered := rank(docs, all, tokenize("upload"), 5)
+	for _, m := range unfiltered {
+		if m.Index == 3 && (len(filtered) != 1 || filtered[0].Score != m.Score) {
+			t.Errorf("filtered score %v, want %v", filtered, m.Score)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package issue
+
+import (
+	"context"
+	"fmt"
+
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+	"gitea.com/gitea/gitea-mcp/pkg/tool"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	SubscribeIssueToolName   = "subscribe_issue"
+	UnsubscribeIssueToolName = "unsubscribe_issue"
+)
+
+var (
+	SubscribeIssueTool = mcp.NewTool(
+		SubscribeIssueToolName,
+		mcp.WithDescription("Subscribe the authenticated user to notifications of an issue or pull request"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+	)
+
+	UnsubscribeIssueTool = mcp.NewTool(
+		UnsubscribeIssueToolName,
+		mcp.WithDescription("Unsubscribe the authenticated user from notifications of an issue or pull request"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+	)
+)
+
+// subscriptionScopes also covers looking up the authenticated user, whose
+// name the subscription endpoints take.
+var subscriptionScopes = []string{tool.WriteScope(tool.ScopeIssue), tool.ReadScope(tool.ScopeUser)}
+
+func init() {
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    SubscribeIssueTool,
+		Handler: SubscribeIssueFn,
+	}, subscriptionScopes...)
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    UnsubscribeIssueTool,
+		Handler: UnsubscribeIssueFn,
+	}, subscriptionScopes...)
+}
+
+func SubscribeIssueFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called SubscribeIssueFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	index, ok := req.GetArguments()["index"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("index is required"))
+	}
+	if _, err := gitea.ClientFromContext(ctx).IssueSubscribe(owner, repo, int64(index)); err != nil {
+		return to.ErrorResult(fmt.Errorf("subscribe %v/%v/issues/%v err: %v", owner, repo, int64(index), err))
+	}
+	return to.TextResult("Subscribe issue success")
+}
+
+func UnsubscribeIssueFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called UnsubscribeIssueFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	index, ok := req.GetArguments()["index"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("index is required"))
+	}
+	if _, err := gitea.ClientFromContext(ctx).IssueUnSubscribe(owner, repo, int64(index)); err != nil {
+		return to.ErrorResult(fmt.Errorf("unsubscribe %v/%v/issues/%v err: %v", owner, repo, int64(index), err))
+	}
+	return to.TextResult("Unsubscribe issue success")
+}
@@ -0,0 +1,468 @@
+package issue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+	"gitea.com/gitea/gitea-mcp/pkg/tool"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	ListIssueTemplatesToolName      = "list_issue_templates"
+	CreateIssueFromTemplateToolName = "create_issue_from_template"
+)
+
+// issue forms have an API since Gitea 1.17
+const templateMinVersion = "1.17.0"
+
+var (
+	ListIssueTemplatesTool = mcp.NewTool(
+		ListIssueTemplatesToolName,
+		mcp.WithDescription("List the issue templates of a repository, markdown templates and YAML issue forms, with their form fields, labels and assignees"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+	)
+
+	CreateIssueFromTemplateTool = mcp.NewTool(
+		CreateIssueFromTemplateToolName,
+		mcp.WithDescription("Create an issue from an issue template: validate the form field values, render the body like the web UI and apply the template's labels, assignees and ref"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithString("template", mcp.Required(), mcp.Description("template name or file name")),
+		mcp.WithString("title", mcp.Description("issue title, appended to the template's title prefix. Required if the template has no title")),
+		mcp.WithObject("fields", mcp.Description("values of an issue form by field id: a string for input and textarea, an option label or a list of them for dropdown, the list of checked option labels for checkboxes")),
+		mcp.WithString("body", mcp.Description("issue body for markdown templates, defaults to the template content")),
+		mcp.WithArray("labels", mcp.Description("label names added to the template's labels"), mcp.Items(map[string]interface{}{"type": "string"})),
+		mcp.WithArray("assignees", mcp.Description("usernames assigned in addition to the template's assignees"), mcp.Items(map[string]interface{}{"type": "string"})),
+		mcp.WithNumber("milestone", tool.NumberOrString(), mcp.Description("milestone ID or title")),
+		mcp.WithBoolean("preview", mcp.Description("validate and render the issue without creating it"), mcp.DefaultBool(false)),
+	)
+)
+
+func init() {
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListIssueTemplatesTool,
+		Handler: ListIssueTemplatesFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    CreateIssueFromTemplateTool,
+		Handler: CreateIssueFromTemplateFn,
+	})
+	Tool.RequireVersion(ListIssueTemplatesToolName, templateMinVersion)
+	Tool.RequireVersion(CreateIssueFromTemplateToolName, templateMinVersion)
+}
+
+// IssueTemplate is an issue template as parsed by Gitea. The SDK's type
+// can't decode checkbox options and lacks assignees and field visibility.
+type IssueTemplate struct {
+	Name      string            `json:"name"`
+	Title     string            `json:"title"`
+	About     string            `json:"about"`
+	Labels    []string          `json:"labels"`
+	Assignees []string          `json:"assignees"`
+	Ref       string            `json:"ref"`
+	Content   string            `json:"content"`
+	Fields    []*IssueFormField `json:"body"`
+	FileName  string            `json:"file_name"`
+}
+
+// IssueFormField is a field of an issue form.
+type IssueFormField struct {
+	Type        string         `json:"type"`
+	ID          string         `json:"id"`
+	Attributes  map[string]any `json:"attributes"`
+	Validations map[string]any `json:"validations"`
+	Visible     []string       `json:"visible,omitempty"`
+}
+
+func (f *IssueFormField) label() string {
+	label, _ := f.Attributes["label"].(string)
+	return label
+}
+
+func (f *IssueFormField) required() bool {
+	required, _ := f.Validations["required"].(bool)
+	return required
+}
+
+// visibleIn reports whether the field shows in the form or in the content
+// of the created issue. Markdown fields only show in the form by default.
+func (f *IssueFormField) visibleIn(where string) bool {
+	if len(f.Visible) == 0 {
+		return where == "form" || f.Type != "markdown"
+	}
+	for _, v := range f.Visible {
+		if v == where {
+			return true
+		}
+	}
+	return false
+}
+
+// formOption is a dropdown or checkboxes option.
+type formOption struct {
+	label    string
+	required bool
+	content  bool
+}
+
+// options returns the dropdown options, which are plain strings, or the
+// checkboxes options, which are objects.
+func (f *IssueFormField) options() []formOption {
+	items, _ := f.Attributes["options"].([]any)
+	options := make([]formOption, 0, len(items))
+	for _, item := range items {
+		switch item := item.(type) {
+		case string:
+			options = append(options, formOption{label: item, content: true})
+		case map[string]any:
+			o := formOption{content: true}
+			o.label, _ = item["label"].(string)
+			o.required, _ = item["required"].(bool)
+			if visible, ok := item["visible"].([]any); ok {
+				o.content = false
+				for _, v := range visible {
+					if v == "content" {
+						o.content = true
+					}
+				}
+			}
+			options = append(options, o)
+		}
+	}
+	return options
+}
+
+func listIssueTemplates(ctx context.Context, owner, repo string) ([]*IssueTemplate, error) {
+	var templates []*IssueTemplate
+	path := fmt.Sprintf("/repos/%s/%s/issue_templates", url.PathEscape(owner), url.PathEscape(repo))
+	if err := gitea.Do(ctx, "GET", path, nil, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func ListIssueTemplatesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListIssueTemplatesFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	templates, err := listIssueTemplates(ctx, owner, repo)
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/issue_templates err: %v", owner, repo, err))
+	}
+	return to.TextResult(templates)
+}
+
+// selected returns the option labels chosen by a dropdown or checkboxes
+// value, which is a label or a list of labels.
+func selected(field *IssueFormField, value any) ([]string, error) {
+	var labels []string
+	switch value := value.(type) {
+	case nil:
+	case string:
+		if value != "" {
+			labels = []string{value}
+		}
+	case []any:
+		labels = to.Strings(value)
+	default:
+		return nil, fmt.Errorf("field %s: expected an option label or a list of them", field.ID)
+	}
+	options := field.options()
+	for _, label := range labels {
+		found := false
+		for _, o := range options {
+			found = found || o.label == label
+		}
+		if !found {
+			return nil, fmt.Errorf("field %s: %q is not one of its options", field.ID, label)
+		}
+	}
+	return labels, nil
+}
+
+// validateField checks the value of a form field against its validations.
+func validateField(field *IssueFormField, value any) error {
+	switch field.Type {
+	case "input", "textarea":
+		s, ok := value.(string)
+		if !ok && value != nil {
+			return fmt.Errorf("field %s: expected a string", field.ID)
+		}
+		if s == "" {
+			if field.required() {
+				return fmt.Errorf("field %s (%s) is required", field.ID, field.label())
+			}
+			return nil
+		}
+		if field.Type != "input" {
+			return nil
+		}
+		if isNumber, _ := field.Validations["is_number"].(bool); isNumber {
+			if _, err := strconv.ParseFloat(s, 64); err != nil {
+				return fmt.Errorf("field %s (%s) must be a number", field.ID, field.label())
+			}
+		}
+		if pattern, _ := field.Validations["regex"].(string); pattern != "" {
+			// like the HTML pattern attribute the web form uses
+			re, err := regexp.Compile("^(?:" + pattern + ")$")
+			if err != nil {
+				return fmt.Errorf("field %s has an invalid regex: %v", field.ID, err)
+			}
+			if !re.MatchString(s) {
+				return fmt.Errorf("field %s (%s) must match %s", field.ID, field.label(), pattern)
+			}
+		}
+	case "dropdown":
+		labels, err := selected(field, value)
+		if err != nil {
+			return err
+		}
+		if len(labels) == 0 && field.required() {
+			return fmt.Errorf("field %s (%s) is required", field.ID, field.label())
+		}
+		if multiple, _ := field.Attributes["multiple"].(bool); !multiple && len(labels) > 1 {
+			return fmt.Errorf("field %s (%s) takes a single option", field.ID, field.label())
+		}
+	case "checkboxes":
+		labels, err := selected(field, value)
+		if err != nil {
+			return err
+		}
+		for _, o := range field.options() {
+			if o.required && !slices.Contains(labels, o.label) {
+				return fmt.Errorf("field %s: %q must be checked", field.ID, o.label)
+			}
+		}
+	}
+	return nil
+}
+
+// formValues applies the defaults of the web form to the given values and
+// validates them, reporting every invalid field.
+func formValues(tmpl *IssueTemplate, values map[string]any) (map[string]any, error) {
+	known := map[string]bool{}
+	result := map[string]any{}
+	var errs []error
+	for _, field := range tmpl.Fields {
+		if field.Type == "markdown" || field.ID == "" {
+			continue
+		}
+		known[field.ID] = true
+		value, ok := values[field.ID]
+		if !ok {
+			switch field.Type {
+			case "input", "textarea":
+				value, _ = field.Attributes["value"].(string)
+			case "dropdown":
+				if i, ok := field.Attributes["default"].(float64); ok && int(i) < len(field.options()) {
+					value = field.options()[int(i)].label
+				}
+			}
+		}
+		if !field.visibleIn("form") {
+			continue
+		}
+		if err := validateField(field, value); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result[field.ID] = value
+	}
+	for id := range values {
+		if !known[id] {
+			errs = append(errs, fmt.Errorf("template has no field %s", id))
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// renderForm renders the body of an issue created from a form like Gitea's
+// web UI: a heading per field followed by its value.
+func renderForm(tmpl *IssueTemplate, values map[string]any) string {
+	const blank = "_No response_\n"
+	var b strings.Builder
+	for _, field := range tmpl.Fields {
+		if field.Type == "markdown" || !field.visibleIn("content") {
+			continue
+		}
+		if hide, _ := field.Attributes["hide_label"].(bool); !hide {
+			fmt.Fprintf(&b, "### %s\n\n", field.label())
+		}
+		value := values[field.ID]
+		switch field.Type {
+		case "checkboxes":
+			labels, _ := selected(field, value)
+			for _, o := range field.options() {
+				if !o.content {
+					continue
+				}
+				checked := " "
+				if slices.Contains(labels, o.label) {
+					checked = "x"
+				}
+				fmt.Fprintf(&b, "- [%s] %s\n", checked, o.label)
+			}
+		case "dropdown":
+			labels, _ := selected(field, value)
+			// in the order of the options, like the web form submits them
+			var checked []string
+			for _, o := range field.options() {
+				if slices.Contains(labels, o.label) {
+					checked = append(checked, o.label)
+				}
+			}
+			if len(checked) == 0 {
+				b.WriteString(blank)
+			} else {
+				fmt.Fprintf(&b, "%s\n", strings.Join(checked, ", "))
+			}
+		case "input":
+			if s, _ := value.(string); s == "" {
+				b.WriteString(blank)
+			} else {
+				fmt.Fprintf(&b, "%s\n", s)
+			}
+		case "textarea":
+			s, _ := value.(string)
+			render, _ := field.Attributes["render"].(string)
+			switch {
+			case s == "":
+				b.WriteString(blank)
+			case render != "":
+				quotes := "```"
+				for strings.Contains(s, quotes) {
+					quotes += "`"
+				}
+				fmt.Fprintf(&b, "%s%s\n%s\n%s\n", quotes, render, s, quotes)
+			default:
+				fmt.Fprintf(&b, "%s\n", s)
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func findTemplate(templates []*IssueTemplate, name string) *IssueTemplate {
+	for _, t := range templates {
+		if strings.EqualFold(t.Name, name) || t.FileName == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// mergeNames appends the names missing from base, ignoring case.
+func mergeNames(base, extra []string) []string {
+	merged := append([]string{}, base...)
+	for _, name := range extra {
+		if !containsFold(merged, name) {
+			merged = append(merged, name)
+		}
+	}
+	return merged
+}
+
+func CreateIssueFromTemplateFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called CreateIssueFromTemplateFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	name, ok := req.GetArguments()["template"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("template is required"))
+	}
+	templates, err := listIssueTemplates(ctx, owner, repo)
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/issue_templates err: %v", owner, repo, err))
+	}
+	tmpl := findTemplate(templates, name)
+	if tmpl == nil {
+		names := make([]string, 0, len(templates))
+		for _, t := range templates {
+			names = append(names, t.Name)
+		}
+		return to.ErrorResult(fmt.Errorf("template %s not found, available: %s", name, strings.Join(names, ", ")))
+	}
+
+	title, _ := req.GetArguments()["title"].(string)
+	if !strings.HasPrefix(title, tmpl.Title) {
+		title = tmpl.Title + title
+	}
+	if strings.TrimSpace(title) == "" {
+		return to.ErrorResult(fmt.Errorf("title is required"))
+	}
+	var body string
+	if len(tmpl.Fields) > 0 {
+		given, _ := req.GetArguments()["fields"].(map[string]any)
+		values, err := formValues(tmpl, given)
+		if err != nil {
+			return to.ErrorResult(fmt.Errorf("invalid fields for template %s:\n%v", tmpl.Name, err))
+		}
+		body = renderForm(tmpl, values)
+	} else {
+		body = tmpl.Content
+		if b, ok := req.GetArguments()["body"].(string); ok && b != "" {
+			body = b
+		}
+	}
+
+	opt := gitea_sdk.CreateIssueOption{
+		Title:     title,
+		Body:      body,
+		Ref:       tmpl.Ref,
+		Assignees: mergeNames(tmpl.Assignees, to.Strings(req.GetArguments()["assignees"])),
+	}
+	labels := mergeNames(tmpl.Labels, to.Strings(req.GetArguments()["labels"]))
+	if preview, _ := req.GetArguments()["preview"].(bool); preview {
+		return to.TextResult(map[string]any{
+			"title":     opt.Title,
+			"body":      opt.Body,
+			"ref":       opt.Ref,
+			"labels":    labels,
+			"assignees": opt.Assignees,
+		})
+	}
+	if len(labels) > 0 {
+		ids, err := resolveLabelIDs(ctx, owner, repo, labels)
+		if err != nil {
+			return to.ErrorResult(err)
+		}
+		opt.Labels = ids
+	}
+	milestone, err := gitea.ResolveMilestoneID(ctx, owner, repo, req.GetArguments()["milestone"])
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	opt.Milestone = milestone
+	issue, _, err := gitea.ClientFromContext(ctx).CreateIssue(owner, repo, opt)
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("create %v/%v/issue err: %v", owner, repo, err))
+	}
+	return to.TextResult(issue)
+}
@@ -0,0 +1,178 @@
+package issue
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// testForm is an issue form as Gitea's issue_templates endpoint returns it.
+const testForm = `{
+	"name": "Bug Report",
+	"title": "[Bug]: ",
+	"body": [
+		{"type": "markdown", "attributes": {"value": "Thanks for reporting"}},
+		{"type": "input", "id": "version", "attributes": {"label": "Version", "value": "1.0"}, "validations": {"required": true, "regex": "\\d+\\.\\d+"}},
+		{"type": "input", "id": "count", "attributes": {"label": "Count"}, "validations": {"is_number": true}},
+		{"type": "textarea", "id": "logs", "attributes": {"label": "Logs", "render": "shell"}},
+		{"type": "textarea", "id": "notes", "attributes": {"label": "Notes"}},
+		{"type": "dropdown", "id": "os", "attributes": {"label": "OS", "options": ["Linux", "macOS", "Windows"], "default": 0}},
+		{"type": "dropdown", "id": "browsers", "attributes": {"label": "Browsers", "multiple": true, "options": ["Firefox", "Chrome"]}, "validations": {"required": true}},
+		{"type": "checkboxes", "id": "terms", "attributes": {"label": "Terms", "options": [{"label": "I searched", "required": true}, {"label": "Hidden", "visible": ["form"]}]}},
+		{"type": "input", "id": "internal", "attributes": {"label": "Internal"}, "visible": ["form"]}
+	]
+}`
+
+func parseTestForm(t *testing.T) *IssueTemplate {
+	t.Helper()
+	var tmpl IssueTemplate
+	if err := json.Unmarshal([]byte(testForm), &tmpl); err != nil {
+		t.Fatal(err)
+	}
+	return &tmpl
+}
+
+func formField(t *testing.T, tmpl *IssueTemplate, id string) *IssueFormField {
+	t.Helper()
+	for _, f := range tmpl.Fields {
+		if f.ID == id {
+			return f
+		}
+	}
+	t.Fatalf("no field %s", id)
+	return nil
+}
+
+func TestValidateField(t *testing.T) {
+	tmpl := parseTestForm(t)
+	tests := []struct {
+		field string
+		value any
+		err   string
+	}{
+		{"version", "1.23", ""},
+		{"version", "", "is required"},
+		{"version", nil, "is required"},
+		{"version", "1.23.0", "must match"},
+		{"version", "x1.2", "must match"},
+		{"version", 1.2, "expected a string"},
+		{"count", "", ""},
+		{"count", "42", ""},
+		{"count", "many", "must be a number"},
+		{"os", "Linux", ""},
+		{"os", "BeOS", "is not one of its options"},
+		{"os", []any{"Linux", "macOS"}, "takes a single option"},
+		{"browsers", []any{"Firefox", "Chrome"}, ""},
+		{"browsers", []any{}, "is required"},
+		{"browsers", 3.0, "expected an option label"},
+		{"terms", []any{"I searched"}, ""},
+		{"terms", []any{"Hidden"}, `"I searched" must be checked`},
+	}
+	for _, tt := range tests {
+		err := validateField(formField(t, tmpl, tt.field), tt.value)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s = %#v: unexpected error %v", tt.field, tt.value, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%s = %#v: error %v, want %q", tt.field, tt.value, err, tt.err)
+		}
+	}
+}
+
+func TestFormValues(t *testing.T) {
+	tmpl := parseTestForm(t)
+	tests := []struct {
+		name   string
+		values map[string]any
+		want   map[string]any
+		errs   []string
+	}{
+		{
+			name:   "defaults",
+			values: map[string]any{"browsers": []any{"Firefox"}, "terms": []any{"I searched"}},
+			want:   map[string]any{"version": "1.0", "os": "Linux"},
+		},
+		{
+			name:   "given values win",
+			values: map[string]any{"version": "2.0", "os": "macOS", "browsers": "Chrome", "terms": []any{"I searched"}},
+			want:   map[string]any{"version": "2.0", "os": "macOS", "browsers": "Chrome"},
+		},
+		{
+			name:   "every invalid field is reported",
+			values: map[string]any{"version": "x", "count": "y", "typo": "z"},
+			errs:   []string{"version", "count", "field browsers (Browsers) is required", `"I searched" must be checked`, "no field typo"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := formValues(tmpl, tt.values)
+			if len(tt.errs) > 0 {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				for _, want := range tt.errs {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("error %q does not mention %q", err, want)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for id, want := range tt.want {
+				if got[id] != want {
+					t.Errorf("%s = %v, want %v", id, got[id], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderForm(t *testing.T) {
+	tmpl := parseTestForm(t)
+	tests := []struct {
+		name   string
+		values map[string]any
+		want   string
+	}{
+		{
+			name: "filled",
+			values: map[string]any{
+				"version":  "1.2",
+				"count":    "3",
+				"logs":     "panic: ```oops```",
+				"notes":    "none",
+				"os":       "Linux",
+				"browsers": []any{"Chrome", "Firefox"},
+				"terms":    []any{"I searched"},
+				"internal": "secret",
+			},
+			want: "### Version\n\n1.2\n\n" +
+				"### Count\n\n3\n\n" +
+				"### Logs\n\n````shell\npanic: ```oops```\n````\n\n" +
+				"### Notes\n\nnone\n\n" +
+				"### OS\n\nLinux\n\n" +
+				"### Browsers\n\nFirefox, Chrome\n\n" +
+				"### Terms\n\n- [x] I searched\n\n",
+		},
+		{
+			name:   "empty",
+			values: map[string]any{},
+			want: "### Version\n\n_No response_\n\n" +
+				"### Count\n\n_No response_\n\n" +
+				"### Logs\n\n_No response_\n\n" +
+				"### Notes\n\n_No response_\n\n" +
+				"### OS\n\n_No response_\n\n" +
+				"### Browsers\n\n_No response_\n\n" +
+				"### Terms\n\n- [ ] I searched\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderForm(tmpl, tt.values); got != tt.want {
+				t.Errorf("renderForm() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,491 @@
+package issue
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+	"gitea.com/gitea/gitea-mcp/pkg/tool"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	StartIssueStopwatchToolName    = "start_issue_stopwatch"
+	StopIssueStopwatchToolName     = "stop_issue_stopwatch"
+	CancelIssueStopwatchToolName   = "cancel_issue_stopwatch"
+	ListMyStopwatchesToolName      = "list_my_stopwatches"
+	AddIssueTrackedTimeToolName    = "add_issue_tracked_time"
+	DeleteIssueTrackedTimeToolName = "delete_issue_tracked_time"
+	ListIssueTrackedTimesToolName  = "list_issue_tracked_times"
+	ListRepoTrackedTimesToolName   = "list_repo_tracked_times"
+	ListMyTrackedTimesToolName     = "list_my_tracked_times"
+)
+
+// maxSummaryEntries bounds the tracked times fetched for a summary.
+const maxSummaryEntries = 10000
+
+var (
+	StartIssueStopwatchTool = mcp.NewTool(
+		StartIssueStopwatchToolName,
+		mcp.WithDescription("Start the stopwatch of the authenticated user on an issue"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+	)
+
+	StopIssueStopwatchTool = mcp.NewTool(
+		StopIssueStopwatchToolName,
+		mcp.WithDescription("Stop the stopwatch on an issue and record the elapsed time as tracked time"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+	)
+
+	CancelIssueStopwatchTool = mcp.NewTool(
+		CancelIssueStopwatchToolName,
+		mcp.WithDescription("Cancel the stopwatch on an issue without recording any time"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+	)
+
+	ListMyStopwatchesTool = mcp.NewTool(
+		ListMyStopwatchesToolName,
+		mcp.WithDescription("List the running stopwatches of the authenticated user"),
+	)
+
+	AddIssueTrackedTimeTool = mcp.NewTool(
+		AddIssueTrackedTimeToolName,
+		mcp.WithDescription("Add tracked time to an issue"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithString("time", mcp.Required(), mcp.Description("time spent, as a duration like 1h30m or a number of seconds")),
+		mcp.WithString("created", mcp.Description("when the time was spent, RFC3339 or YYYY-MM-DD, defaults to now")),
+		mcp.WithString("user", mcp.Description("user the time is tracked for, defaults to the authenticated user. Requires admin rights on the repository")),
+	)
+
+	DeleteIssueTrackedTimeTool = mcp.NewTool(
+		DeleteIssueTrackedTimeToolName,
+		mcp.WithDescription("Delete a tracked time entry of an issue"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithNumber("id", mcp.Required(), mcp.Description("tracked time id")),
+	)
+
+	ListIssueTrackedTimesTool = mcp.NewTool(
+		ListIssueTrackedTimesToolName,
+		mcp.WithDescription("List the tracked times of an issue"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+		mcp.WithString("user", mcp.Description("only times tracked by this user")),
+		mcp.WithString("since", mcp.Description("only times tracked since this date, RFC3339 or YYYY-MM-DD")),
+		mcp.WithString("before", mcp.Description("only times tracked before this date, RFC3339 or YYYY-MM-DD")),
+		mcp.WithBoolean("summary", mcp.Description("return totals by user and issue over all matching times instead of a page of entries"), mcp.DefaultBool(false)),
+		mcp.WithNumber("page", mcp.Description("page number"), mcp.DefaultNumber(1)),
+		mcp.WithNumber("pageSize", mcp.Description("page size"), mcp.DefaultNumber(100)),
+	)
+
+	ListRepoTrackedTimesTool = mcp.NewTool(
+		ListRepoTrackedTimesToolName,
+		mcp.WithDescription("List the tracked times of a repository"),
+		mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+		mcp.WithString("user", mcp.Description("only times tracked by this user")),
+		mcp.WithString("since", mcp.Description("only times tracked since this date, RFC3339 or YYYY-MM-DD")),
+		mcp.WithString("before", mcp.Description("only times tracked before this date, RFC3339 or YYYY-MM-DD")),
+		mcp.WithBoolean("summary", mcp.Description("return totals by user and issue over all matching times instead of a page of entries"), mcp.DefaultBool(false)),
+		mcp.WithNumber("page", mcp.Description("page number"), mcp.DefaultNumber(1)),
+		mcp.WithNumber("pageSize", mcp.Description("page size"), mcp.DefaultNumber(100)),
+	)
+
+	ListMyTrackedTimesTool = mcp.NewTool(
+		ListMyTrackedTimesToolName,
+		mcp.WithDescription("List the times tracked by the authenticated user across all repositories"),
+		mcp.WithString("since", mcp.Description("only times tracked since this date, RFC3339 or YYYY-MM-DD")),
+		mcp.WithString("before", mcp.Description("only times tracked before this date, RFC3339 or YYYY-MM-DD")),
+		mcp.WithBoolean("summary", mcp.Description("return totals by user and issue over all matching times instead of a page of entries"), mcp.DefaultBool(false)),
+		mcp.WithNumber("page", mcp.Description("page number"), mcp.DefaultNumber(1)),
+		mcp.WithNumber("pageSize", mcp.Description("page size"), mcp.DefaultNumber(100)),
+	)
+)
+
+func init() {
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    StartIssueStopwatchTool,
+		Handler: StartIssueStopwatchFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    StopIssueStopwatchTool,
+		Handler: StopIssueStopwatchFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    CancelIssueStopwatchTool,
+		Handler: CancelIssueStopwatchFn,
+	})
+	// the stopwatches and times of the authenticated user are user resources
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListMyStopwatchesTool,
+		Handler: ListMyStopwatchesFn,
+	}, tool.ReadScope(tool.ScopeUser))
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    AddIssueTrackedTimeTool,
+		Handler: AddIssueTrackedTimeFn,
+	})
+	Tool.RegisterWrite(server.ServerTool{
+		Tool:    DeleteIssueTrackedTimeTool,
+		Handler: DeleteIssueTrackedTimeFn,
+	})
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListIssueTrackedTimesTool,
+		Handler: ListIssueTrackedTimesFn,
+	})
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListRepoTrackedTimesTool,
+		Handler: ListRepoTrackedTimesFn,
+	})
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    ListMyTrackedTimesTool,
+		Handler: ListMyTrackedTimesFn,
+	}, tool.ReadScope(tool.ScopeUser))
+}
+
+func issueArgs(req mcp.CallToolRequest) (string, string, int64, error) {
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return "", "", 0, fmt.Errorf("owner is required")
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return "", "", 0, fmt.Errorf("repo is required")
+	}
+	index, ok := req.GetArguments()["index"].(float64)
+	if !ok {
+		return "", "", 0, fmt.Errorf("index is required")
+	}
+	return owner, repo, int64(index), nil
+}
+
+func StartIssueStopwatchFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called StartIssueStopwatchFn")
+	owner, repo, index, err := issueArgs(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	if _, err := gitea.ClientFromContext(ctx).StartIssueStopWatch(owner, repo, index); err != nil {
+		return to.ErrorResult(fmt.Errorf("start %v/%v/issues/%v stopwatch err: %v", owner, repo, index, err))
+	}
+	return to.TextResult("Start stopwatch success")
+}
+
+func StopIssueStopwatchFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called StopIssueStopwatchFn")
+	owner, repo, index, err := issueArgs(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	if _, err := gitea.ClientFromContext(ctx).StopIssueStopWatch(owner, repo, index); err != nil {
+		return to.ErrorResult(fmt.Errorf("stop %v/%v/issues/%v stopwatch err: %v", owner, repo, index, err))
+	}
+	return to.TextResult("Stop stopwatch success")
+}
+
+func CancelIssueStopwatchFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called CancelIssueStopwatchFn")
+	owner, repo, index, err := issueArgs(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	if _, err := gitea.ClientFromContext(ctx).DeleteIssueStopwatch(owner, repo, index); err != nil {
+		return to.ErrorResult(fmt.Errorf("cancel %v/%v/issues/%v stopwatch err: %v", owner, repo, index, err))
+	}
+	return to.TextResult("Cancel stopwatch success")
+}
+
+func ListMyStopwatchesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListMyStopwatchesFn")
+	stopwatches, _, err := gitea.ClientFromContext(ctx).GetMyStopwatches()
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get stopwatches err: %v", err))
+	}
+	return to.TextResult(stopwatches)
+}
+
+// parseTrackedTime parses a Go duration such as 1h30m, or a number of seconds.
+func parseTrackedTime(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if seconds <= 0 {
+			return 0, fmt.Errorf("time must be positive")
+		}
+		return seconds, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: use a duration like 1h30m or a number of seconds", s)
+	}
+	if d < time.Second {
+		return 0, fmt.Errorf("time must be at least one second")
+	}
+	return int64(d / time.Second), nil
+}
+
+func AddIssueTrackedTimeFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called AddIssueTrackedTimeFn")
+	owner, repo, index, err := issueArgs(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	timeArg, ok := req.GetArguments()["time"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("time is required"))
+	}
+	opt := gitea_sdk.AddTimeOption{}
+	if opt.Time, err = parseTrackedTime(timeArg); err != nil {
+		return to.ErrorResult(err)
+	}
+	if created, ok := req.GetArguments()["created"].(string); ok && created != "" {
+		if opt.Created, err = to.Time(created); err != nil {
+			return to.ErrorResult(fmt.Errorf("created: %v", err))
+		}
+	}
+	if user, ok := req.GetArguments()["user"].(string); ok {
+		opt.User = user
+	}
+	trackedTime, _, err := gitea.ClientFromContext(ctx).AddTime(owner, repo, index, opt)
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("add %v/%v/issues/%v tracked time err: %v", owner, repo, index, err))
+	}
+	return to.TextResult(trackedTime)
+}
+
+func DeleteIssueTrackedTimeFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called DeleteIssueTrackedTimeFn")
+	owner, repo, index, err := issueArgs(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	id, ok := req.GetArguments()["id"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("id is required"))
+	}
+	if _, err := gitea.ClientFromContext(ctx).DeleteTime(owner, repo, index, int64(id)); err != nil {
+		return to.ErrorResult(fmt.Errorf("delete %v/%v/issues/%v tracked time %v err: %v", owner, repo, index, int64(id), err))
+	}
+	return to.TextResult("Delete tracked time success")
+}
+
+// trackedTimesOptions reads the filter and paging arguments shared by the
+// tracked time list tools.
+func trackedTimesOptions(req mcp.CallToolRequest) (gitea_sdk.ListTrackedTimesOptions, bool, error) {
+	opt := gitea_sdk.ListTrackedTimesOptions{}
+	if user, ok := req.GetArguments()["user"].(string); ok {
+		opt.User = user
+	}
+	var err error
+	if since, ok := req.GetArguments()["since"].(string); ok && since != "" {
+		if opt.Since, err = to.Time(since); err != nil {
+			return opt, false, fmt.Errorf("since: %v", err)
+		}
+	}
+	if before, ok := req.GetArguments()["before"].(string); ok && before != "" {
+		if opt.Before, err = to.Time(before); err != nil {
+			return opt, false, fmt.Errorf("before: %v", err)
+		}
+	}
+	page, ok := req.GetArguments()["page"].(float64)
+	if !ok {
+		page = 1
+	}
+	pageSize, ok := req.GetArguments()["pageSize"].(float64)
+	if !ok {
+		pageSize = 100
+	}
+	opt.ListOptions = gitea_sdk.ListOptions{
+		Page:     int(page),
+		PageSize: int(pageSize),
+	}
+	summary, _ := req.GetArguments()["summary"].(bool)
+	return opt, summary, nil
+}
+
+// listTrackedTimes returns the requested page of tracked times, or with
+// summary every matching entry totalled by summarizeTrackedTimes.
+func listTrackedTimes(opt gitea_sdk.ListTrackedTimesOptions, summary bool, list func(gitea_sdk.ListTrackedTimesOptions) ([]*gitea_sdk.TrackedTime, error)) (any, error) {
+	if !summary {
+		return list(opt)
+	}
+	const pageSize = 50
+	var all []*gitea_sdk.TrackedTime
+	for page := 1; len(all) < maxSummaryEntries; page++ {
+		opt.ListOptions = gitea_sdk.ListOptions{Page: page, PageSize: pageSize}
+		times, err := list(opt)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, times...)
+		if len(times) < pageSize {
+			return summarizeTrackedTimes(all, false), nil
+		}
+	}
+	return summarizeTrackedTimes(all, true), nil
+}
+
+// TimeTotal is the time tracked by a user or on an issue.
+type TimeTotal struct {
+	User     string `json:"user,omitempty"`
+	Issue    string `json:"issue,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Entries  int    `json:"entries"`
+	Seconds  int64  `json:"seconds"`
+	Duration string `json:"duration"`
+}
+
+// TrackedTimeSummary totals tracked times by user and by issue.
+type TrackedTimeSummary struct {
+	Entries  int          `json:"entries"`
+	Seconds  int64        `json:"seconds"`
+	Duration string       `json:"duration"`
+	ByUser   []*TimeTotal `json:"by_user"`
+	ByIssue  []*TimeTotal `json:"by_issue"`
+	// Truncated is set when more entries matched than were summarized.
+	Truncated bool `json:"truncated"`
+}
+
+func formatSeconds(seconds int64) string {
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+func trackedTimeIssue(t *gitea_sdk.TrackedTime) (string, string) {
+	if t.Issue == nil {
+		return fmt.Sprintf("issue id %d", t.IssueID), ""
+	}
+	if t.Issue.Repository != nil && t.Issue.Repository.FullName != "" {
+		return fmt.Sprintf("%s#%d", t.Issue.Repository.FullName, t.Issue.Index), t.Issue.Title
+	}
+	return fmt.Sprintf("#%d", t.Issue.Index), t.Issue.Title
+}
+
+func summarizeTrackedTimes(times []*gitea_sdk.TrackedTime, truncated bool) *TrackedTimeSummary {
+	summary := &TrackedTimeSummary{
+		ByUser:    []*TimeTotal{},
+		ByIssue:   []*TimeTotal{},
+		Truncated: truncated,
+	}
+	users := map[string]*TimeTotal{}
+	issues := map[string]*TimeTotal{}
+	for _, t := range times {
+		summary.Entries++
+		summary.Seconds += t.Time
+
+		u, ok := users[t.UserName]
+		if !ok {
+			u = &TimeTotal{User: t.UserName}
+			users[t.UserName] = u
+			summary.ByUser = append(summary.ByUser, u)
+		}
+		u.Entries++
+		u.Seconds += t.Time
+
+		key, title := trackedTimeIssue(t)
+		i, ok := issues[key]
+		if !ok {
+			i = &TimeTotal{Issue: key, Title: title}
+			issues[key] = i
+			summary.ByIssue = append(summary.ByIssue, i)
+		}
+		i.Entries++
+		i.Seconds += t.Time
+	}
+	summary.Duration = formatSeconds(summary.Seconds)
+	for _, totals := range [][]*TimeTotal{summary.ByUser, summary.ByIssue} {
+		for _, t := range totals {
+			t.Duration = formatSeconds(t.Seconds)
+		}
+		sort.SliceStable(totals, func(i, j int) bool { return totals[i].Seconds > totals[j].Seconds })
+	}
+	return summary
+}
+
+func ListIssueTrackedTimesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListIssueTrackedTimesFn")
+	owner, repo, index, err := issueArgs(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	opt, summary, err := trackedTimesOptions(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	result, err := listTrackedTimes(opt, summary, func(opt gitea_sdk.ListTrackedTimesOptions) ([]*gitea_sdk.TrackedTime, error) {
+		times, _, err := gitea.ClientFromContext(ctx).ListIssueTrackedTimes(owner, repo, index, opt)
+		return times, err
+	})
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/issues/%v tracked times err: %v", owner, repo, index, err))
+	}
+	return to.TextResult(result)
+}
+
+func ListRepoTrackedTimesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListRepoTrackedTimesFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	opt, summary, err := trackedTimesOptions(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	result, err := listTrackedTimes(opt, summary, func(opt gitea_sdk.ListTrackedTimesOptions) ([]*gitea_sdk.TrackedTime, error) {
+		times, _, err := gitea.ClientFromContext(ctx).ListRepoTrackedTimes(owner, repo, opt)
+		return times, err
+	})
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v tracked times err: %v", owner, repo, err))
+	}
+	return to.TextResult(result)
+}
+
+func ListMyTrackedTimesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called ListMyTrackedTimesFn")
+	opt, summary, err := trackedTimesOptions(req)
+	if err != nil {
+		return to.ErrorResult(err)
+	}
+	// the SDK's GetMyTrackedTimes takes no date range or paging
+	result, err := listTrackedTimes(opt, summary, func(opt gitea_sdk.ListTrackedTimesOptions) ([]*gitea_sdk.TrackedTime, error) {
+		query := url.Values{}
+		query.Set("page", strconv.Itoa(opt.Page))
+		query.Set("limit", strconv.Itoa(opt.PageSize))
+		if !opt.Since.IsZero() {
+			query.Set("since", opt.Since.Format(time.RFC3339))
+		}
+		if !opt.Before.IsZero() {
+			query.Set("before", opt.Before.Format(time.RFC3339))
+		}
+		var times []*gitea_sdk.TrackedTime
+		err := gitea.Do(ctx, "GET", "/user/times?"+query.Encode(), nil, &times)
+		return times, err
+	})
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get tracked times err: %v", err))
+	}
+	return to.TextResult(result)
+}
@@ -0,0 +1,330 @@
+package issue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/to"
+
+	gitea_sdk "code.gitea.io/sdk/gitea"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	GetIssueTimelineToolName = "get_issue_timeline"
+)
+
+const (
+	// the timeline has an API since Gitea 1.15
+	timelineMinVersion = "1.15.0"
+
+	maxTimelineEvents = 5000
+	compactBodyLength = 200
+)
+
+var GetIssueTimelineTool = mcp.NewTool(
+	GetIssueTimelineToolName,
+	mcp.WithDescription("Get the timeline of an issue or pull request in chronological order: comments, label, assignee, milestone and state changes, references, reviews, pushes and more. At most 5000 events are returned, truncated is set when there are more"),
+	mcp.WithString("owner", mcp.Required(), mcp.Description("repository owner")),
+	mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
+	mcp.WithNumber("index", mcp.Required(), mcp.Description("repository issue index")),
+	mcp.WithString("since", mcp.Description("only events updated since this date, RFC3339 or YYYY-MM-DD")),
+	mcp.WithString("before", mcp.Description("only events updated before this date, RFC3339 or YYYY-MM-DD")),
+	mcp.WithBoolean("compact", mcp.Description("summarize each event as who did what when instead of returning the raw events"), mcp.DefaultBool(false)),
+)
+
+func init() {
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    GetIssueTimelineTool,
+		Handler: GetIssueTimelineFn,
+	})
+	Tool.RequireVersion(GetIssueTimelineToolName, timelineMinVersion)
+}
+
+// TimelineEvent is an event of an issue timeline. The SDK's TimelineComment
+// lacks most event fields and decodes label as a list.
+type TimelineEvent struct {
+	ID              int64                  `json:"id"`
+	Type            string                 `json:"type"`
+	HTMLURL         string                 `json:"html_url"`
+	Poster          *gitea_sdk.User        `json:"user"`
+	OriginalAuthor  string                 `json:"original_author,omitempty"`
+	Body            string                 `json:"body"`
+	Created         time.Time              `json:"created_at"`
+	Updated         time.Time              `json:"updated_at"`
+	OldTitle        string                 `json:"old_title,omitempty"`
+	NewTitle        string                 `json:"new_title,omitempty"`
+	OldRef          string                 `json:"old_ref,omitempty"`
+	NewRef          string                 `json:"new_ref,omitempty"`
+	RefIssue        *gitea_sdk.Issue       `json:"ref_issue,omitempty"`
+	RefComment      *gitea_sdk.Comment     `json:"ref_comment,omitempty"`
+	RefAction       string                 `json:"ref_action,omitempty"`
+	RefCommitSHA    string                 `json:"ref_commit_sha,omitempty"`
+	ReviewID        int64                  `json:"review_id,omitempty"`
+	Label           *gitea_sdk.Label       `json:"label,omitempty"`
+	Assignee        *gitea_sdk.User        `json:"assignee,omitempty"`
+	AssigneeTeam    *gitea_sdk.Team        `json:"assignee_team,omitempty"`
+	RemovedAssignee bool                   `json:"removed_assignee,omitempty"`
+	ResolveDoer     *gitea_sdk.User        `json:"resolve_doer,omitempty"`
+	DependentIssue  *gitea_sdk.Issue       `json:"dependent_issue,omitempty"`
+	Milestone       *gitea_sdk.Milestone   `json:"milestone,omitempty"`
+	OldMilestone    *gitea_sdk.Milestone   `json:"old_milestone,omitempty"`
+	TrackedTime     *gitea_sdk.TrackedTime `json:"tracked_time,omitempty"`
+}
+
+// CompactEvent summarizes a timeline event.
+type CompactEvent struct {
+	Time   time.Time `json:"time"`
+	User   string    `json:"user"`
+	Action string    `json:"action"`
+}
+
+func GetIssueTimelineFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called GetIssueTimelineFn")
+	owner, ok := req.GetArguments()["owner"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("owner is required"))
+	}
+	repo, ok := req.GetArguments()["repo"].(string)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("repo is required"))
+	}
+	index, ok := req.GetArguments()["index"].(float64)
+	if !ok {
+		return to.ErrorResult(fmt.Errorf("index is required"))
+	}
+	query := url.Values{}
+	for _, arg := range []string{"since", "before"} {
+		if v, ok := req.GetArguments()[arg].(string); ok && v != "" {
+			t, err := to.Time(v)
+			if err != nil {
+				return to.ErrorResult(fmt.Errorf("%s: %v", arg, err))
+			}
+			query.Set(arg, t.Format(time.RFC3339))
+		}
+	}
+	compact, _ := req.GetArguments()["compact"].(bool)
+
+	events, truncated, err := listTimeline(ctx, owner, repo, int64(index), query)
+	if err != nil {
+		return to.ErrorResult(fmt.Errorf("get %v/%v/issues/%v/timeline err: %v", owner, repo, int64(index), err))
+	}
+	if !compact {
+		return to.TextResult(timelineResult{Events: events, Truncated: truncated})
+	}
+	summary := make([]CompactEvent, 0, len(events))
+	for _, e := range events {
+		summary = append(summary, CompactEvent{
+			Time:   e.Created,
+			User:   eventUser(e),
+			Action: describeEvent(e),
+		})
+	}
+	return to.TextResult(timelineResult{Events: summary, Truncated: truncated})
+}
+
+// timelineResult is the result of get_issue_timeline. Events holds
+// TimelineEvents, or CompactEvents when compact.
+type timelineResult struct {
+	Events any `json:"events"`
+	// Truncated is set when the timeline has more than maxTimelineEvents
+	// events.
+	Truncated bool `json:"truncated"`
+}
+
+// listTimeline fetches the pages of the timeline, which Gitea returns in
+// chronological order, up to maxTimelineEvents events. It reports whether
+// the timeline has more events.
+func listTimeline(ctx context.Context, owner, repo string, index int64, query url.Values) ([]*TimelineEvent, bool, error) {
+	const pageSize = 50
+	events := []*TimelineEvent{}
+	for page := 1; len(events) <= maxTimelineEvents; page++ {
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", strconv.Itoa(pageSize))
+		var batch []*TimelineEvent
+		if err := gitea.Do(ctx, "GET", issuePath(owner, repo, index, "timeline")+"?"+query.Encode(), nil, &batch); err != nil {
+			return nil, false, err
+		}
+		events = append(events, batch...)
+		if len(batch) < pageSize {
+			break
+		}
+	}
+	if len(events) > maxTimelineEvents {
+		return events[:maxTimelineEvents], true, nil
+	}
+	return events, false, nil
+}
+
+func eventUser(e *TimelineEvent) string {
+	if e.Poster != nil && e.Poster.UserName != "" {
+		return e.Poster.UserName
+	}
+	return e.OriginalAuthor
+}
+
+func userName(u *gitea_sdk.User) string {
+	if u == nil {
+		return "ghost"
+	}
+	return u.UserName
+}
+
+func issueRef(issue *gitea_sdk.Issue) string {
+	if issue == nil {
+		return "an issue"
+	}
+	if issue.Repository != nil && issue.Repository.FullName != "" {
+		return fmt.Sprintf("%s#%d", issue.Repository.FullName, issue.Index)
+	}
+	return fmt.Sprintf("#%d", issue.Index)
+}
+
+func milestoneTitle(m *gitea_sdk.Milestone) string {
+	if m == nil {
+		return ""
+	}
+	return m.Title
+}
+
+// abbreviate shortens a comment body to one line of at most
+// compactBodyLength runes.
+func abbreviate(body string) string {
+	body = strings.Join(strings.Fields(body), " ")
+	if r := []rune(body); len(r) > compactBodyLength {
+		return string(r[:compactBodyLength]) + "…"
+	}
+	return body
+}
+
+// describeEvent summarizes what happened in an event, in the terms of the
+// Gitea web UI.
+func describeEvent(e *TimelineEvent) string {
+	switch e.Type {
+	case "comment":
+		return "commented: " + abbreviate(e.Body)
+	case "close":
+		return "closed"
+	case "reopen":
+		return "reopened"
+	case "merge_pull":
+		return "merged"
+	case "change_title":
+		return fmt.Sprintf("changed title from %q to %q", e.OldTitle, e.NewTitle)
+	case "label":
+		name := ""
+		if e.Label != nil {
+			name = e.Label.Name
+		}
+		// the body of a label event is "1" when the label was added
+		if e.Body == "1" {
+			return "added label " + name
+		}
+		return "removed label " + name
+	case "assignees":
+		target := userName(e.Assignee)
+		if e.AssigneeTeam != nil {
+			target = "team " + e.AssigneeTeam.Name
+		}
+		if e.RemovedAssignee {
+			return "unassigned " + target
+		}
+		return "assigned " + target
+	case "milestone":
+		switch oldTitle, newTitle := milestoneTitle(e.OldMilestone), milestoneTitle(e.Milestone); {
+		case newTitle == "":
+			return "removed milestone " + oldTitle
+		case oldTitle == "":
+			return "added milestone " + newTitle
+		default:
+			return fmt.Sprintf("changed milestone from %s to %s", oldTitle, newTitle)
+		}
+	case "issue_ref", "pull_ref", "comment_ref":
+		return "referenced this from " + issueRef(e.RefIssue)
+	case "commit_ref":
+		return "referenced this in commit " + e.RefCommitSHA
+	case "change_issue_ref", "change_target_branch":
+		return fmt.Sprintf("changed ref from %s to %s", e.OldRef, e.NewRef)
+	case "delete_branch":
+		return "deleted branch " + e.OldRef
+	case "pull_push":
+		var push struct {
+			IsForcePush bool     `json:"is_force_push"`
+			CommitIDs   []string `json:"commit_ids"`
+		}
+		if json.Unmarshal([]byte(e.Body), &push) == nil {
+			if push.IsForcePush {
+				return "force-pushed"
+			}
+			return fmt.Sprintf("pushed %d commits", len(push.CommitIDs))
+		}
+		return "pushed commits"
+	case "review":
+		if e.Body == "" {
+			return "reviewed"
+		}
+		return "reviewed: " + abbreviate(e.Body)
+	case "code":
+		return "commented on code: " + abbreviate(e.Body)
+	case "review_request":
+		target := userName(e.Assignee)
+		if e.AssigneeTeam != nil {
+			target = "team " + e.AssigneeTeam.Name
+		}
+		if e.RemovedAssignee {
+			return "removed review request for " + target
+		}
+		return "requested review from " + target
+	case "dismiss_review":
+		return "dismissed a review"
+	case "add_dependency":
+		return "added dependency " + issueRef(e.DependentIssue)
+	case "remove_dependency":
+		return "removed dependency " + issueRef(e.DependentIssue)
+	case "added_deadline":
+		return "added due date " + e.Body
+	case "modified_deadline":
+		// the body holds "new|old"
+		if dates := strings.SplitN(e.Body, "|", 2); len(dates) == 2 {
+			return fmt.Sprintf("changed due date from %s to %s", dates[1], dates[0])
+		}
+		return "changed due date"
+	case "removed_deadline":
+		return "removed due date " + e.Body
+	case "start_tracking":
+		return "started working"
+	case "stop_tracking", "add_time_manual":
+		return "spent " + e.Body
+	case "cancel_tracking":
+		return "canceled time tracking"
+	case "delete_time_manual":
+		return "deleted spent time " + e.Body
+	case "lock":
+		return "locked"
+	case "unlock":
+		return "unlocked"
+	case "pin":
+		return "pinned"
+	case "unpin":
+		return "unpinned"
+	case "project", "project_board":
+		return "changed project"
+	case "pull_scheduled_merge":
+		return "scheduled the pull request to merge"
+	case "pull_cancel_scheduled_merge":
+		return "canceled the scheduled merge"
+	default:
+		if e.Body != "" {
+			return e.Type + ": " + abbreviate(e.Body)
+		}
+		return e.Type
+	}
+}
@@ -1,7 +1,9 @@
 package operation
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 
 	"gitea.com/gitea/gitea-mcp/operation/issue"
 	"gitea.com/gitea/gitea-mcp/operation/pull"
@@ -10,36 +12,83 @@ import (
 	"gitea.com/gitea/gitea-mcp/operation/user"
 	"gitea.com/gitea/gitea-mcp/operation/version"
 	"gitea.com/gitea/gitea-mcp/pkg/flag"
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
 	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/tool"
+	"gitea.com/gitea/gitea-mcp/pkg/tracing"
 
 	"github.com/mark3labs/mcp-go/server"
 )
 
 var mcpServer *server.MCPServer
 
-func RegisterTool(s *server.MCPServer) {
-	// User Tool
-	s.AddTools(user.Tool.Tools()...)
+func init() {
+	tool.AddFilter(func() tool.Filter { return requireServerVersion })
+}
 
-	// Repo Tool
-	s.AddTools(repo.Tool.Tools()...)
+// requireServerVersion hides tools the connected server is too old for.
+func requireServerVersion(info tool.Info) (bool, string) {
+	if info.MinVersion == "" || gitea.SupportsVersion(info.MinVersion) {
+		return true, ""
+	}
+	return false, fmt.Sprintf("requires Gitea >= %s", info.MinVersion)
+}
 
-	// Issue Tool
-	s.AddTools(issue.Tool.Tools()...)
+// Toolset is the group of tools registered by one operation package.
+type Toolset struct {
+	Name string
+	Tool *tool.Tool
+}
 
-	// Pull Tool
-	s.AddTools(pull.Tool.Tools()...)
+// Toolsets returns every tool registry in registration order.
+func Toolsets() []Toolset {
+	return []Toolset{
+		{Name: "user", Tool: user.Tool},
+		{Name: "repo", Tool: repo.Tool},
+		{Name: "issue", Tool: issue.Tool},
+		{Name: "pull", Tool: pull.Tool},
+		{Name: "search", Tool: search.Tool},
+		{Name: "version", Tool: version.Tool},
+	}
+}
 
-	// Search Tool
-	s.AddTools(search.Tool.Tools()...)
+// Tools returns every tool enabled by the current configuration.
+func Tools() []server.ServerTool {
+	var tools []server.ServerTool
+	for _, ts := range Toolsets() {
+		tools = append(tools, ts.Tool.Tools()...)
+	}
+	return tools
+}
 
-	// Version Tool
-	s.AddTools(version.Tool.Tools()...)
+// GetTool looks up an enabled tool by name.
+func GetTool(name string) (server.ServerTool, bool) {
+	for _, t := range Tools() {
+		if t.Tool.Name == name {
+			return t, true
+		}
+	}
+	return server.ServerTool{}, false
+}
 
-	s.DeleteTools("")
+func RegisterTool(s *server.MCPServer) {
+	s.AddTools(Tools()...)
+	for name, reason := range tool.Hidden() {
+		log.Debugf("Tool %s is hidden: %s", name, reason)
+	}
 }
 
 func Run() error {
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Errorf("shutdown tracing err: %v", err)
+		}
+	}()
+
 	mcpServer = newMCPServer(flag.Version)
 	RegisterTool(mcpServer)
 	switch flag.Mode {
@@ -48,13 +97,17 @@ func Run() error {
 			return err
 		}
 	case "sse":
-		sseServer := server.NewSSEServer(mcpServer)
+		sseServer := server.NewSSEServer(mcpServer,
+			server.WithSSEContextFunc(requestContext),
+		)
 		log.Infof("Gitea MCP SSE server listening on :%d", flag.Port)
 		if err := sseServer.Start(fmt.Sprintf(":%d", flag.Port)); err != nil {
 			return err
 		}
 	case "http":
-		httpServer := server.NewStreamableHTTPServer(mcpServer)
+		httpServer := server.NewStreamableHTTPServer(mcpServer,
+			server.WithHTTPContextFunc(requestContext),
+		)
 		log.Infof("Gitea MCP HTTP server listening on :%d", flag.Port)
 		if err := httpServer.Start(fmt.Sprintf(":%d", flag.Port)); err != nil {
 			return err
@@ -72,5 +125,26 @@ func newMCPServer(version string) *server.MCPServer {
 		server.WithToolCapabilities(true),
 		server.WithLogging(),
 		server.WithRecovery(),
+		server.WithToolHandlerMiddleware(tracing.ToolMiddleware),
+		server.WithToolHandlerMiddleware(requireSessionScopes),
+		server.WithToolHandlerMiddleware(RequireSudo),
+		server.WithToolFilter(filterSessionTools),
 	)
 }
+
+// requestContext carries the trace context, the caller's own access token,
+// if the HTTP or SSE request has an Authorization header, and the user
+// identity to impersonate into tool calls. This lets one server act for several users.
+func requestContext(ctx context.Context, r *http.Request) context.Context {
+	ctx = tracing.ContextFromRequest(ctx, r)
+	if token := gitea.TokenFromRequest(r); token != "" {
+		log.AddSecret(token)
+		ctx = gitea.WithToken(ctx, token)
+	}
+	if flag.Sudo {
+		if identity := gitea.SudoFromRequest(r); identity != "" {
+			ctx = gitea.WithSudo(ctx, identity)
+		}
+	}
+	return ctx
+}
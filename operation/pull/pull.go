@@ -14,7 +14,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-var Tool = tool.New()
+var Tool = tool.New(tool.ScopeRepository)
 
 const (
 	GetPullRequestByIndexToolName = "get_pull_request_by_index"
@@ -38,7 +38,7 @@ var (
 		mcp.WithString("repo", mcp.Required(), mcp.Description("repository name")),
 		mcp.WithString("state", mcp.Description("state"), mcp.Enum("open", "closed", "all"), mcp.DefaultString("all")),
 		mcp.WithString("sort", mcp.Description("sort"), mcp.Enum("oldest", "recentupdate", "leastupdate", "mostcomment", "leastcomment", "priority"), mcp.DefaultString("recentupdate")),
-		mcp.WithNumber("milestone", mcp.Description("milestone")),
+		mcp.WithNumber("milestone", tool.NumberOrString(), mcp.Description("milestone ID or title")),
 		mcp.WithNumber("page", mcp.Description("page number"), mcp.DefaultNumber(1)),
 		mcp.WithNumber("pageSize", mcp.Description("page size"), mcp.DefaultNumber(100)),
 	)
@@ -84,7 +84,7 @@ func GetPullRequestByIndexFn(ctx context.Context, req mcp.CallToolRequest) (*mcp
 	if !ok {
 		return to.ErrorResult(fmt.Errorf("index is required"))
 	}
-	pr, _, err := gitea.Client().GetPullRequest(owner, repo, int64(index))
+	pr, _, err := gitea.ClientFromContext(ctx).GetPullRequest(owner, repo, int64(index))
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("get %v/%v/pr/%v err: %v", owner, repo, int64(index), err))
 	}
@@ -107,7 +107,10 @@ func ListRepoPullRequestsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.
 	if !ok {
 		sort = "recentupdate"
 	}
-	milestone, _ := req.GetArguments()["milestone"].(float64)
+	milestone, err := gitea.ResolveMilestoneID(ctx, owner, repo, req.GetArguments()["milestone"])
+	if err != nil {
+		return to.ErrorResult(err)
+	}
 	page, ok := req.GetArguments()["page"].(float64)
 	if !ok {
 		page = 1
@@ -119,13 +122,13 @@ func ListRepoPullRequestsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.
 	opt := gitea_sdk.ListPullRequestsOptions{
 		State:     gitea_sdk.StateType(state),
 		Sort:      sort,
-		Milestone: int64(milestone),
+		Milestone: milestone,
 		ListOptions: gitea_sdk.ListOptions{
 			Page:     int(page),
 			PageSize: int(pageSize),
 		},
 	}
-	pullRequests, _, err := gitea.Client().ListRepoPullRequests(owner, repo, opt)
+	pullRequests, _, err := gitea.ClientFromContext(ctx).ListRepoPullRequests(owner, repo, opt)
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("list %v/%v/pull_requests err: %v", owner, repo, err))
 	}
@@ -159,7 +162,7 @@ func CreatePullRequestFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.Cal
 	if !ok {
 		return to.ErrorResult(fmt.Errorf("base is required"))
 	}
-	pr, _, err := gitea.Client().CreatePullRequest(owner, repo, gitea_sdk.CreatePullRequestOption{
+	pr, _, err := gitea.ClientFromContext(ctx).CreatePullRequest(owner, repo, gitea_sdk.CreatePullRequestOption{
 		Title: title,
 		Body:  body,
 		Head:  head,
@@ -50,10 +50,12 @@ func init() {
 		Tool:    CreateBranchTool,
 		Handler: CreateBranchFn,
 	})
+	Tool.RequireVersion(CreateBranchToolName, "1.13.0")
 	Tool.RegisterWrite(server.ServerTool{
 		Tool:    DeleteBranchTool,
 		Handler: DeleteBranchFn,
 	})
+	Tool.RequireVersion(DeleteBranchToolName, "1.12.0")
 	Tool.RegisterRead(server.ServerTool{
 		Tool:    ListBranchesTool,
 		Handler: ListBranchesFn,
@@ -76,7 +78,7 @@ func CreateBranchFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallTool
 	}
 	oldBranch, _ := req.GetArguments()["old_branch"].(string)
 
-	_, _, err := gitea.Client().CreateBranch(owner, repo, gitea_sdk.CreateBranchOption{
+	_, _, err := gitea.ClientFromContext(ctx).CreateBranch(owner, repo, gitea_sdk.CreateBranchOption{
 		BranchName:    branch,
 		OldBranchName: oldBranch,
 	})
@@ -101,7 +103,7 @@ func DeleteBranchFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallTool
 	if !ok {
 		return to.ErrorResult(fmt.Errorf("branch is required"))
 	}
-	_, _, err := gitea.Client().DeleteRepoBranch(owner, repo, branch)
+	_, _, err := gitea.ClientFromContext(ctx).DeleteRepoBranch(owner, repo, branch)
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("delete branch error: %v", err))
 	}
@@ -125,7 +127,7 @@ func ListBranchesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallTool
 			PageSize: 100,
 		},
 	}
-	branches, _, err := gitea.Client().ListRepoBranches(owner, repo, opt)
+	branches, _, err := gitea.ClientFromContext(ctx).ListRepoBranches(owner, repo, opt)
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("list branches error: %v", err))
 	}
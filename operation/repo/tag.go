@@ -62,14 +62,17 @@ func init() {
 		Tool:    CreateTagTool,
 		Handler: CreateTagFn,
 	})
+	Tool.RequireVersion(CreateTagToolName, "1.15.0")
 	Tool.RegisterWrite(server.ServerTool{
 		Tool:    DeleteTagTool,
 		Handler: DeleteTagFn,
 	})
+	Tool.RequireVersion(DeleteTagToolName, "1.14.0")
 	Tool.RegisterRead(server.ServerTool{
 		Tool:    GetTagTool,
 		Handler: GetTagFn,
 	})
+	Tool.RequireVersion(GetTagToolName, "1.15.0")
 	Tool.RegisterRead(server.ServerTool{
 		Tool:    ListTagsTool,
 		Handler: ListTagsFn,
@@ -102,7 +105,7 @@ func CreateTagFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolRes
 	target, _ := req.GetArguments()["target"].(string)
 	message, _ := req.GetArguments()["message"].(string)
 
-	_, _, err := gitea.Client().CreateTag(owner, repo, gitea_sdk.CreateTagOption{
+	_, _, err := gitea.ClientFromContext(ctx).CreateTag(owner, repo, gitea_sdk.CreateTagOption{
 		TagName: tagName,
 		Target:  target,
 		Message: message,
@@ -129,7 +132,7 @@ func DeleteTagFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolRes
 		return nil, fmt.Errorf("tag_name is required")
 	}
 
-	_, err := gitea.Client().DeleteTag(owner, repo, tagName)
+	_, err := gitea.ClientFromContext(ctx).DeleteTag(owner, repo, tagName)
 	if err != nil {
 		return nil, fmt.Errorf("delete tag error: %v", err)
 	}
@@ -152,7 +155,7 @@ func GetTagFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult
 		return nil, fmt.Errorf("tag_name is required")
 	}
 
-	tag, _, err := gitea.Client().GetTag(owner, repo, tagName)
+	tag, _, err := gitea.ClientFromContext(ctx).GetTag(owner, repo, tagName)
 	if err != nil {
 		return nil, fmt.Errorf("get tag error: %v", err)
 	}
@@ -173,7 +176,7 @@ func ListTagsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResu
 	page, _ := req.GetArguments()["page"].(float64)
 	pageSize, _ := req.GetArguments()["pageSize"].(float64)
 
-	tags, _, err := gitea.Client().ListRepoTags(owner, repo, gitea_sdk.ListRepoTagsOptions{
+	tags, _, err := gitea.ClientFromContext(ctx).ListRepoTags(owner, repo, gitea_sdk.ListRepoTagsOptions{
 		ListOptions: gitea_sdk.ListOptions{
 			Page:     int(page),
 			PageSize: int(pageSize),
@@ -0,0 +1,88 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"gitea.com/gitea/gitea-mcp/pkg/flag"
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/tool"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func init() {
+	tool.AddFilter(requireTokenScopes)
+}
+
+// requireTokenScopes hides tools the configured token has no scope for. If
+// the scopes cannot be detected, every tool stays available.
+func requireTokenScopes() tool.Filter {
+	var scopes gitea.Scopes
+	if flag.Token != "" {
+		scopes, _ = gitea.TokenScopes(flag.Token)
+	}
+	return func(info tool.Info) (bool, string) {
+		if scopes == nil {
+			return true, ""
+		}
+		if missing := scopes.Missing(info.Scopes); len(missing) > 0 {
+			return false, "token lacks scope " + strings.Join(missing, ", ")
+		}
+		return true, ""
+	}
+}
+
+// sessionScopes returns the scopes of the token sent with the request, or
+// nil when the request carries no token of its own or the scopes cannot be
+// detected.
+func sessionScopes(ctx context.Context) gitea.Scopes {
+	token := gitea.TokenFromContext(ctx)
+	if token == "" {
+		return nil
+	}
+	scopes, err := gitea.TokenScopes(token)
+	if err != nil {
+		return nil
+	}
+	return scopes
+}
+
+func requiredScopes() map[string][]string {
+	required := make(map[string][]string)
+	for _, info := range tool.All() {
+		required[info.Tool.Name] = info.Scopes
+	}
+	return required
+}
+
+// filterSessionTools hides tools from tools/list that the token of an
+// HTTP or SSE user lacks the scopes for.
+func filterSessionTools(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+	scopes := sessionScopes(ctx)
+	if scopes == nil {
+		return tools
+	}
+	required := requiredScopes()
+	filtered := make([]mcp.Tool, 0, len(tools))
+	for _, t := range tools {
+		if len(scopes.Missing(required[t.Name])) == 0 {
+			filtered = append(filtered, t)
+		}
+	}
+	return filtered
+}
+
+// requireSessionScopes rejects calls to tools hidden by filterSessionTools.
+func requireSessionScopes(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if scopes := sessionScopes(ctx); scopes != nil {
+			if missing := scopes.Missing(requiredScopes()[req.Params.Name]); len(missing) > 0 {
+				return nil, fmt.Errorf("tool %s requires token scope %s", req.Params.Name, strings.Join(missing, ", "))
+			}
+		}
+		return next(ctx, req)
+	}
+}
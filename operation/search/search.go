@@ -3,6 +3,10 @@ package search
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"gitea.com/gitea/gitea-mcp/pkg/gitea"
 	"gitea.com/gitea/gitea-mcp/pkg/log"
@@ -15,12 +19,13 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-var Tool = tool.New()
+var Tool = tool.New(tool.ScopeRepository)
 
 const (
 	SearchUsersToolName    = "search_users"
 	SearchOrgTeamsToolName = "search_org_teams"
 	SearchReposToolName    = "search_repos"
+	SearchIssuesToolName   = "search_issues"
 )
 
 var (
@@ -56,21 +61,47 @@ var (
 		mcp.WithNumber("page", mcp.Description("Page"), mcp.DefaultNumber(1)),
 		mcp.WithNumber("pageSize", mcp.Description("PageSize"), mcp.DefaultNumber(100)),
 	)
+
+	SearchIssuesTool = mcp.NewTool(
+		SearchIssuesToolName,
+		mcp.WithDescription("search issues and pull requests across all repositories the user can access"),
+		mcp.WithString("query", mcp.Description("search keyword in title and body")),
+		mcp.WithString("state", mcp.Description("issue state"), mcp.Enum("open", "closed", "all"), mcp.DefaultString("open")),
+		mcp.WithString("type", mcp.Description("whether to search issues, pull requests or both"), mcp.Enum("issues", "pulls", "all"), mcp.DefaultString("all")),
+		mcp.WithArray("labels", mcp.Description("only issues with all of these label names"), mcp.Items(map[string]interface{}{"type": "string"})),
+		mcp.WithArray("milestones", mcp.Description("only issues in one of these milestone titles"), mcp.Items(map[string]interface{}{"type": "string"})),
+		mcp.WithString("owner", mcp.Description("only repositories of this user or organization")),
+		mcp.WithString("team", mcp.Description("only repositories of this team, requires owner to be an organization")),
+		mcp.WithBoolean("assigned", mcp.Description("only issues assigned to the authenticated user")),
+		mcp.WithBoolean("created", mcp.Description("only issues created by the authenticated user")),
+		mcp.WithBoolean("mentioned", mcp.Description("only issues mentioning the authenticated user")),
+		mcp.WithBoolean("review_requested", mcp.Description("only pull requests the authenticated user is requested to review")),
+		mcp.WithString("since", mcp.Description("only issues updated at or after this time, YYYY-MM-DD or RFC 3339")),
+		mcp.WithString("before", mcp.Description("only issues updated at or before this time, YYYY-MM-DD or RFC 3339")),
+		mcp.WithNumber("page", mcp.Description("Page"), mcp.DefaultNumber(1)),
+		mcp.WithNumber("pageSize", mcp.Description("PageSize"), mcp.DefaultNumber(100)),
+	)
 )
 
 func init() {
 	Tool.RegisterRead(server.ServerTool{
 		Tool:    SearchUsersTool,
 		Handler: SearchUsersFn,
-	})
+	}, tool.ReadScope(tool.ScopeUser))
 	Tool.RegisterRead(server.ServerTool{
 		Tool:    SearOrgTeamsTool,
 		Handler: SearchOrgTeamsFn,
-	})
+	}, tool.ReadScope(tool.ScopeOrganization))
 	Tool.RegisterRead(server.ServerTool{
 		Tool:    SearchReposTool,
 		Handler: SearchReposFn,
 	})
+	Tool.RegisterRead(server.ServerTool{
+		Tool:    SearchIssuesTool,
+		Handler: SearchIssuesFn,
+	}, tool.ReadScope(tool.ScopeIssue))
+	// the user filters of the issue search are supported since Gitea 1.16
+	Tool.RequireVersion(SearchIssuesToolName, "1.16.0")
 }
 
 func SearchUsersFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -94,7 +125,7 @@ func SearchUsersFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolR
 			PageSize: int(pageSize),
 		},
 	}
-	users, _, err := gitea.Client().SearchUsers(opt)
+	users, _, err := gitea.ClientFromContext(ctx).SearchUsers(opt)
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("search users err: %v", err))
 	}
@@ -128,7 +159,7 @@ func SearchOrgTeamsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallTo
 			PageSize: int(pageSize),
 		},
 	}
-	teams, _, err := gitea.Client().SearchOrgTeams(org, &opt)
+	teams, _, err := gitea.ClientFromContext(ctx).SearchOrgTeams(org, &opt)
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("search organization teams error: %v", err))
 	}
@@ -178,9 +209,70 @@ func SearchReposFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolR
 			PageSize: int(pageSize),
 		},
 	}
-	repos, _, err := gitea.Client().SearchRepos(opt)
+	repos, _, err := gitea.ClientFromContext(ctx).SearchRepos(opt)
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("search repos error: %v", err))
 	}
 	return to.TextResult(repos)
 }
+
+// SearchIssuesFn calls the global issue search endpoint directly, since the
+// SDK's ListIssues lacks the assigned, created, mentioned and
+// review_requested switches.
+func SearchIssuesFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	log.Debugf("Called SearchIssuesFn")
+	state, ok := req.GetArguments()["state"].(string)
+	if !ok {
+		state = "open"
+	}
+	page, ok := req.GetArguments()["page"].(float64)
+	if !ok {
+		page = 1
+	}
+	pageSize, ok := req.GetArguments()["pageSize"].(float64)
+	if !ok {
+		pageSize = 100
+	}
+	query := url.Values{}
+	query.Set("state", state)
+	query.Set("page", strconv.Itoa(int(page)))
+	query.Set("limit", strconv.Itoa(int(pageSize)))
+	if issueType, ok := req.GetArguments()["type"].(string); ok && issueType != "all" {
+		query.Set("type", issueType)
+	}
+	for _, p := range []struct{ arg, param string }{
+		{"query", "q"},
+		{"owner", "owner"},
+		{"team", "team"},
+	} {
+		if v, ok := req.GetArguments()[p.arg].(string); ok && v != "" {
+			query.Set(p.param, v)
+		}
+	}
+	if labels := to.Strings(req.GetArguments()["labels"]); len(labels) > 0 {
+		query.Set("labels", strings.Join(labels, ","))
+	}
+	if milestones := to.Strings(req.GetArguments()["milestones"]); len(milestones) > 0 {
+		query.Set("milestones", strings.Join(milestones, ","))
+	}
+	for _, name := range []string{"assigned", "created", "mentioned", "review_requested"} {
+		if v, ok := req.GetArguments()[name].(bool); ok && v {
+			query.Set(name, "true")
+		}
+	}
+	for _, name := range []string{"since", "before"} {
+		if v, ok := req.GetArguments()[name].(string); ok && v != "" {
+			t, err := to.Time(v)
+			if err != nil {
+				return to.ErrorResult(err)
+			}
+			query.Set(name, t.Format(time.RFC3339))
+		}
+	}
+
+	var issues []*gitea_sdk.Issue
+	if err := gitea.Do(ctx, "GET", "/repos/issues/search?"+query.Encode(), nil, &issues); err != nil {
+		return to.ErrorResult(fmt.Errorf("search issues error: %v", err))
+	}
+	return to.TextResult(issues)
+}
@@ -0,0 +1,35 @@
+package operation
+
+import (
+	"context"
+	"fmt"
+
+	"gitea.com/gitea/gitea-mcp/pkg/flag"
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// RequireSudo rejects tool calls that, with impersonation enabled, would act
+// for no user or for a user outside --sudo-allow, and logs who each call is
+// made as.
+func RequireSudo(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if !flag.Sudo {
+			return next(ctx, req)
+		}
+		identity := gitea.SudoIdentity(ctx)
+		user := gitea.Sudo(ctx)
+		if user == "" {
+			return nil, fmt.Errorf("tool %s needs a user to impersonate: send the %s header or set --sudo-user", req.Params.Name, flag.SudoHeader)
+		}
+		if !gitea.SudoAllowed(user) {
+			log.Warnf("Rejected tool %s: impersonating %s (identity %q) is not allowed", req.Params.Name, user, identity)
+			return nil, fmt.Errorf("impersonating user %s is not allowed", user)
+		}
+		log.Infof("Tool %s called as %s (identity %q)", req.Params.Name, user, identity)
+		return next(ctx, req)
+	}
+}
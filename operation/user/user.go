@@ -19,7 +19,7 @@ const (
 	GetUserOrgsToolName   = "get_user_orgs"
 )
 
-var Tool = tool.New()
+var Tool = tool.New(tool.ScopeUser)
 
 var (
 	GetMyUserInfoTool = mcp.NewTool(
@@ -44,12 +44,12 @@ func init() {
 	Tool.RegisterRead(server.ServerTool{
 		Tool:    GetUserOrgsTool,
 		Handler: GetUserOrgsFn,
-	})
+	}, tool.ReadScope(tool.ScopeUser), tool.ReadScope(tool.ScopeOrganization))
 }
 
 func GetUserInfoFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	log.Debugf("Called GetUserInfoFn")
-	user, _, err := gitea.Client().GetMyUserInfo()
+	user, _, err := gitea.ClientFromContext(ctx).GetMyUserInfo()
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("get user info err: %v", err))
 	}
@@ -73,7 +73,7 @@ func GetUserOrgsFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolR
 			PageSize: int(pageSize),
 		},
 	}
-	orgs, _, err := gitea.Client().ListMyOrgs(opt)
+	orgs, _, err := gitea.ClientFromContext(ctx).ListMyOrgs(opt)
 	if err != nil {
 		return to.ErrorResult(fmt.Errorf("get user orgs err: %v", err))
 	}
@@ -2,9 +2,9 @@ package version
 
 import (
 	"context"
-	"fmt"
 
 	"gitea.com/gitea/gitea-mcp/pkg/flag"
+	"gitea.com/gitea/gitea-mcp/pkg/gitea"
 	"gitea.com/gitea/gitea-mcp/pkg/log"
 	"gitea.com/gitea/gitea-mcp/pkg/to"
 	"gitea.com/gitea/gitea-mcp/pkg/tool"
@@ -13,7 +13,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 )
 
-var Tool = tool.New()
+var Tool = tool.New("")
 
 const (
 	GetGiteaMCPServerVersion = "get_gitea_mcp_server_version"
@@ -21,7 +21,7 @@ const (
 
 var GetGiteaMCPServerVersionTool = mcp.NewTool(
 	GetGiteaMCPServerVersion,
-	mcp.WithDescription("Get Gitea MCP Server Version"),
+	mcp.WithDescription("Get Gitea MCP Server Version, the connected Gitea server version and its supported features"),
 )
 
 func init() {
@@ -31,11 +31,33 @@ func init() {
 	})
 }
 
+type VersionResult struct {
+	Version     string              `json:"version"`
+	Server      gitea.ServerInfo    `json:"server"`
+	TokenScopes []string            `json:"token_scopes,omitempty"`
+	ToolScopes  map[string][]string `json:"tool_scopes"`
+	HiddenTools map[string]string   `json:"hidden_tools,omitempty"`
+}
+
 func GetGiteaMCPServerVersionFn(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	log.Debugf("Called GetGiteaMCPServerVersionFn")
 	version := flag.Version
 	if version == "" {
 		version = "dev"
 	}
-	return to.TextResult(fmt.Sprintf("Gitea MCP Server version: %v", version))
+	result := VersionResult{
+		Version:     version,
+		Server:      gitea.Server(),
+		ToolScopes:  make(map[string][]string),
+		HiddenTools: tool.Hidden(),
+	}
+	for _, info := range tool.All() {
+		result.ToolScopes[info.Tool.Name] = info.Scopes
+	}
+	if token := gitea.Token(ctx); token != "" {
+		if scopes, err := gitea.TokenScopes(token); err == nil {
+			result.TokenScopes = scopes.List()
+		}
+	}
+	return to.TextResult(result)
 }
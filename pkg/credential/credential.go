@@ -0,0 +1,159 @@
+package credential
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Credential is an access token stored by `gitea-mcp login` for one host.
+type Credential struct {
+	Host      string    `json:"host"`
+	User      string    `json:"user"`
+	Token     string    `json:"token"`
+	TokenName string    `json:"token_name,omitempty"`
+	Scopes    []string  `json:"scopes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type store struct {
+	// Default is the host of the last login, used when no host is configured.
+	Default     string       `json:"default"`
+	Credentials []Credential `json:"credentials"`
+}
+
+// Path returns the credentials file, $HOME/.gitea-mcp/credentials.json.
+func Path() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".gitea-mcp", "credentials.json")
+}
+
+// NormalizeHost strips the trailing slash so hosts compare equal however
+// they were typed.
+func NormalizeHost(host string) string {
+	return strings.TrimSuffix(strings.TrimSpace(host), "/")
+}
+
+// Get returns the credential of host, or of the default host when host is
+// empty. It returns nil without error when nothing is stored.
+func Get(host string) (*Credential, error) {
+	s, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if host == "" {
+		host = s.Default
+	}
+	host = NormalizeHost(host)
+	for _, c := range s.Credentials {
+		if c.Host == host {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+// Save stores c, replacing any credential of the same host, and makes its
+// host the default.
+func Save(c Credential) error {
+	s, err := load()
+	if err != nil {
+		return err
+	}
+	c.Host = NormalizeHost(c.Host)
+	creds := s.Credentials[:0]
+	for _, old := range s.Credentials {
+		if old.Host != c.Host {
+			creds = append(creds, old)
+		}
+	}
+	s.Credentials = append(creds, c)
+	s.Default = c.Host
+	return s.save()
+}
+
+// Delete removes the credential of host, or of the default host when host
+// is empty, and returns it. It returns nil without error when nothing is
+// stored.
+func Delete(host string) (*Credential, error) {
+	s, err := load()
+	if err != nil {
+		return nil, err
+	}
+	if host == "" {
+		host = s.Default
+	}
+	host = NormalizeHost(host)
+	var removed *Credential
+	creds := s.Credentials[:0]
+	for _, c := range s.Credentials {
+		if c.Host == host {
+			removed = &c
+			continue
+		}
+		creds = append(creds, c)
+	}
+	if removed == nil {
+		return nil, nil
+	}
+	s.Credentials = creds
+	if s.Default == host {
+		s.Default = ""
+		if len(creds) > 0 {
+			s.Default = creds[len(creds)-1].Host
+		}
+	}
+	return removed, s.save()
+}
+
+func load() (*store, error) {
+	s := &store{}
+	data, err := os.ReadFile(Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read credentials err: %v", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parse credentials %s err: %v", Path(), err)
+	}
+	return s, nil
+}
+
+// save writes the store readable by the current user only, replacing the
+// file atomically so a failed write never loses other hosts' tokens.
+func (s *store) save() error {
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create credentials dir err: %v", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".credentials-*.json")
+	if err != nil {
+		return fmt.Errorf("write credentials err: %v", err)
+	}
+	defer os.Remove(f.Name())
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return fmt.Errorf("write credentials err: %v", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("write credentials err: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("write credentials err: %v", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("write credentials err: %v", err)
+	}
+	return nil
+}
@@ -7,7 +7,27 @@ var (
 	Version string
 	Mode    string
 
-	Insecure bool
+	Insecure   bool
+	CACert     string
+	ClientCert string
+	ClientKey  string
+	Proxy      string
+
 	ReadOnly bool
 	Debug    bool
+
+	Sudo       bool
+	SudoUser   string
+	SudoHeader string
+	SudoAllow  []string
+	SudoMap    map[string]string
+
+	LogPath       string
+	LogLevel      string
+	LogFormat     string
+	LogMaxSize    int
+	LogStderr     bool
+	LogRedactArgs []string
+
+	OTLPEndpoint string
 )
@@ -0,0 +1,179 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+
+	"gitea.com/gitea/gitea-mcp/pkg/flag"
+)
+
+// Do calls a Gitea API endpoint the SDK does not cover, authenticated and
+// impersonating like ClientFromContext. path is relative to /api/v1. body,
+// if not nil, is sent as JSON and a successful response is decoded into
+// result, if not nil.
+func Do(ctx context.Context, method, path string, body, result any) error {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+	req, err := newRequest(ctx, method, apiURL(path), r)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return send(req, result)
+}
+
+// Upload posts a file as the multipart form field of a Gitea API endpoint,
+// like Do otherwise.
+func Upload(ctx context.Context, path, field, filename, contentType string, content []byte, result any) error {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, field, filename))
+	header.Set("Content-Type", contentType)
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(content); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	req, err := newRequest(ctx, "POST", apiURL(path), &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return send(req, result)
+}
+
+// Download fetches a file served by Gitea, such as an attachment, reading at
+// most maxSize bytes. The token is only sent to the configured host.
+func Download(ctx context.Context, rawURL string, maxSize int64) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	host, err := url.Parse(flag.Host)
+	if err != nil {
+		return nil, err
+	}
+	var req *http.Request
+	if strings.EqualFold(u.Host, host.Host) {
+		req, err = newRequest(ctx, "GET", rawURL, nil)
+	} else {
+		options() // initializes httpClient
+		req, err = http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "*/*")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	if resp.ContentLength > maxSize {
+		return nil, fmt.Errorf("file is %d bytes, more than the limit of %d", resp.ContentLength, maxSize)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("file is more than the limit of %d bytes", maxSize)
+	}
+	return data, nil
+}
+
+func apiURL(path string) string {
+	return strings.TrimSuffix(flag.Host, "/") + "/api/v1" + path
+}
+
+// newRequest creates a request carrying the token and sudo user of ctx.
+func newRequest(ctx context.Context, method, rawURL string, body io.Reader) (*http.Request, error) {
+	options() // initializes httpClient
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if token := Token(ctx); token != "" {
+		req.Header.Set("Authorization", "token "+token)
+	}
+	if user := Sudo(ctx); user != "" {
+		req.Header.Set("Sudo", user)
+	}
+	return req, nil
+}
+
+// send sends an API request and decodes a successful JSON response into
+// result, if not nil.
+func send(req *http.Request, result any) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return &StatusError{Status: resp.Status, Code: resp.StatusCode, Message: apiErr.Message}
+		}
+		return &StatusError{Status: resp.Status, Code: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("decode response err: %v", err)
+	}
+	return nil
+}
+
+// StatusError is an error response of the Gitea API.
+type StatusError struct {
+	Status  string
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return e.Status + ": " + e.Message
+}
+
+// IsNotFound reports whether err is a 404 Not Found response of Do.
+func IsNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound
+}
@@ -1,48 +1,148 @@
 package gitea
 
 import (
-	"crypto/tls"
+	"context"
+	"io"
 	"net/http"
 	"sync"
 
 	"gitea.com/gitea/gitea-mcp/pkg/flag"
 	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/tool"
+	"gitea.com/gitea/gitea-mcp/pkg/tracing"
 
 	"code.gitea.io/sdk/gitea"
+	"github.com/hashicorp/go-version"
 )
 
 var (
 	client     *gitea.Client
 	clientOnce sync.Once
+
+	clientOpts     []gitea.ClientOption
+	clientOptsOnce sync.Once
+	httpClient     *http.Client
 )
 
-func Client() *gitea.Client {
-	clientOnce.Do(func() {
-		var err error
-		if client != nil {
-			return
+// options returns the client options shared by every client: token and the
+// HTTP transport with its TLS and proxy settings.
+func options() []gitea.ClientOption {
+	clientOptsOnce.Do(func() {
+		transport, err := newTransport()
+		if err != nil {
+			log.Fatalf("configure gitea transport err: %v", err)
 		}
-
-		httpClient := &http.Client{
-			Transport: http.DefaultTransport,
+		httpClient = &http.Client{
+			Transport: transport,
 		}
 
 		opts := []gitea.ClientOption{
 			gitea.SetToken(flag.Token),
+			gitea.SetHTTPClient(httpClient),
 		}
-		if flag.Insecure {
-			httpClient.Transport.(*http.Transport).TLSClientConfig = &tls.Config{
-				InsecureSkipVerify: true,
-			}
-		}
-		opts = append(opts, gitea.SetHTTPClient(httpClient))
 		if flag.Debug {
-			opts = append(opts, gitea.SetDebugMode())
+			httpClient.Transport = &debugTransport{next: httpClient.Transport}
+		}
+		httpClient.Transport = &statusTransport{next: httpClient.Transport}
+		httpClient.Transport = tracing.Transport(httpClient.Transport)
+		clientOpts = opts
+	})
+	return append([]gitea.ClientOption{}, clientOpts...)
+}
+
+func Client() *gitea.Client {
+	clientOnce.Do(func() {
+		var err error
+		if client != nil {
+			return
 		}
-		client, err = gitea.NewClient(flag.Host, opts...)
+
+		client, err = gitea.NewClient(flag.Host, options()...)
 		if err != nil {
 			log.Fatalf("create gitea client err: %v", err)
 		}
 	})
 	return client
 }
+
+// BasicAuthClient returns a client authenticating with username, password and
+// an optional one-time password instead of a token, as required to manage
+// access tokens.
+func BasicAuthClient(username, password, otp string) (*gitea.Client, error) {
+	opts := append(options(),
+		gitea.SetToken(""),
+		gitea.SetBasicAuth(username, password),
+	)
+	if otp != "" {
+		opts = append(opts, gitea.SetOTP(otp))
+	}
+	return gitea.NewClient(flag.Host, opts...)
+}
+
+// ClientFromContext returns a client whose requests are bound to ctx, so they
+// are cancelled with the tool call and traced as its children. It uses the
+// caller's token when one was attached with WithToken, and impersonates the
+// user returned by Sudo.
+func ClientFromContext(ctx context.Context) *gitea.Client {
+	// the version is already detected, skip the SDK's own lookup per client
+	v := Server().GiteaVersion
+	if _, err := version.NewVersion(v); err != nil {
+		v = ""
+	}
+	opts := append(options(),
+		gitea.SetContext(ctx),
+		gitea.SetGiteaVersion(v),
+	)
+	if token := TokenFromContext(ctx); token != "" {
+		opts = append(opts, gitea.SetToken(token))
+	}
+	if user := Sudo(ctx); user != "" {
+		opts = append(opts, gitea.SetSudo(user))
+	}
+	c, err := gitea.NewClient(flag.Host, opts...)
+	if err != nil {
+		log.Errorf("create gitea client for request err: %v", err)
+		return Client()
+	}
+	return c
+}
+
+// debugTransport logs outgoing requests through the redacting logger. It is
+// used instead of the SDK's SetDebugMode, which prints unredacted headers to
+// stdout and would corrupt the stdio transport.
+type debugTransport struct {
+	next http.RoundTripper
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body string
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			b, _ := io.ReadAll(rc)
+			rc.Close()
+			body = string(b)
+		}
+	}
+	log.Debugf("%s: %s\nHeader: %v\nBody: %s", req.Method, req.URL, req.Header, body)
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		log.Debugf("%s: %s err: %v", req.Method, req.URL, err)
+		return nil, err
+	}
+	log.Debugf("Response: %s: %s %s", req.Method, req.URL, resp.Status)
+	return resp, nil
+}
+
+// statusTransport reports 404 responses to the tool call a request belongs
+// to, so it can hint at the Gitea version the tool requires.
+type statusTransport struct {
+	next http.RoundTripper
+}
+
+func (t *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusNotFound {
+		tool.RecordNotFound(req.Context())
+	}
+	return resp, err
+}
@@ -0,0 +1,66 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+// ResolveMilestoneID returns the ID of the milestone given as a number, or as
+// a string holding a milestone ID or title. A string of digits is an ID, so a
+// milestone titled "2024" has to be given by its ID. 0 means no milestone, as
+// does a missing or empty argument.
+func ResolveMilestoneID(ctx context.Context, owner, repo string, milestone any) (int64, error) {
+	switch v := milestone.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return int64(v), nil
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return 0, nil
+		}
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return id, nil
+		}
+		const pageSize = 50
+		for page := 1; ; page++ {
+			milestones, _, err := ClientFromContext(ctx).ListRepoMilestones(owner, repo, gitea.ListMilestoneOption{
+				ListOptions: gitea.ListOptions{Page: page, PageSize: pageSize},
+				State:       gitea.StateAll,
+			})
+			if err != nil {
+				return 0, fmt.Errorf("get %v/%v/milestones err: %v", owner, repo, err)
+			}
+			for _, m := range milestones {
+				if strings.EqualFold(m.Title, v) {
+					return m.ID, nil
+				}
+			}
+			if len(milestones) < pageSize {
+				break
+			}
+		}
+		return 0, fmt.Errorf("milestone %q not found in %v/%v", v, owner, repo)
+	default:
+		return 0, fmt.Errorf("milestone must be an ID or a title")
+	}
+}
+
+// MilestoneGiven reports whether a milestone argument holds a milestone, or
+// an explicit 0 for none. Editing tools leave the milestone alone otherwise,
+// so an empty string does not remove it.
+func MilestoneGiven(milestone any) bool {
+	switch v := milestone.(type) {
+	case float64:
+		return true
+	case string:
+		return strings.TrimSpace(v) != ""
+	default:
+		return false
+	}
+}
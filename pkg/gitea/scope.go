@@ -0,0 +1,215 @@
+package gitea
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gitea.com/gitea/gitea-mcp/pkg/flag"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/tool"
+)
+
+// Scopes is the set of scopes granted to an access token.
+type Scopes map[string]bool
+
+// Has reports whether scope is granted. A write scope implies the read scope
+// of the same category.
+func (s Scopes) Has(scope string) bool {
+	if s[scope] {
+		return true
+	}
+	if category, ok := strings.CutPrefix(scope, "read:"); ok {
+		return s[tool.WriteScope(category)]
+	}
+	return false
+}
+
+// Missing returns the scopes in required that are not granted.
+func (s Scopes) Missing(required []string) []string {
+	var missing []string
+	for _, scope := range required {
+		if !s.Has(scope) {
+			missing = append(missing, scope)
+		}
+	}
+	return missing
+}
+
+// List returns the granted scopes in sorted order.
+func (s Scopes) List() []string {
+	list := make([]string, 0, len(s))
+	for scope, ok := range s {
+		if ok {
+			list = append(list, scope)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// scopeProbe is a request Gitea rejects with a scope error before doing
+// anything else when the token lacks the scope. Read probes are harmless
+// reads. Write probes delete ID 0, which never exists, under an owner name
+// Gitea does not allow, so they cannot change anything once the scope check
+// passed.
+type scopeProbe struct {
+	scope  string
+	method string
+	path   string
+}
+
+// probeOwner is not a valid user or organization name, as names cannot start
+// with a dash.
+const probeOwner = "-gitea-mcp-scope-probe"
+
+var scopeProbes = []scopeProbe{
+	{tool.ReadScope(tool.ScopeUser), "GET", "/user"},
+	{tool.WriteScope(tool.ScopeUser), "DELETE", "/user/keys/0"},
+	{tool.ReadScope(tool.ScopeRepository), "GET", "/user/repos?limit=1"},
+	{tool.WriteScope(tool.ScopeRepository), "DELETE", "/repos/" + probeOwner + "/" + probeOwner + "/hooks/0"},
+	{tool.ReadScope(tool.ScopeIssue), "GET", "/repos/issues/search?limit=1"},
+	{tool.WriteScope(tool.ScopeIssue), "DELETE", "/repos/" + probeOwner + "/" + probeOwner + "/issues/comments/0"},
+	{tool.ReadScope(tool.ScopeOrganization), "GET", "/orgs?limit=1"},
+	{tool.WriteScope(tool.ScopeOrganization), "DELETE", "/orgs/" + probeOwner + "/hooks/0"},
+}
+
+const (
+	// tokenScopesTTL is how long detected scopes are used before probing
+	// again, e.g. in case the token was deleted.
+	tokenScopesTTL = time.Hour
+	// tokenScopesErrorTTL is how long a failed detection is reported before
+	// probing again, so an unreachable host or a bad token is not probed for
+	// every tool.
+	tokenScopesErrorTTL = time.Minute
+	// maxTokenScopes bounds the tokens whose scopes are cached.
+	maxTokenScopes = 1000
+)
+
+// scopeResult is the detection of the scopes of one token. done is closed
+// once the probes finished, after which the other fields are set.
+type scopeResult struct {
+	done       chan struct{}
+	scopes     Scopes
+	err        error
+	detectedAt time.Time
+}
+
+// expired reports whether the result is finished and too old to be used.
+func (r *scopeResult) expired() bool {
+	select {
+	case <-r.done:
+	default:
+		return false
+	}
+	ttl := tokenScopesTTL
+	if r.err != nil {
+		ttl = tokenScopesErrorTTL
+	}
+	return time.Since(r.detectedAt) >= ttl
+}
+
+var (
+	// tokenScopes is keyed by the SHA-256 hash of the token, so tokens are
+	// not kept in memory longer than their requests.
+	tokenScopes   = map[[sha256.Size]byte]*scopeResult{}
+	tokenScopesMu sync.Mutex
+)
+
+// TokenScopes determines the scopes granted to token by probing the API, and
+// caches the result per token for tokenScopesTTL, or a failure for
+// tokenScopesErrorTTL. Concurrent calls for the same token share one
+// detection. Tokens created before Gitea had scoped tokens pass every probe
+// and are reported with all scopes.
+func TokenScopes(token string) (Scopes, error) {
+	key := sha256.Sum256([]byte(token))
+	tokenScopesMu.Lock()
+	r, ok := tokenScopes[key]
+	if ok && !r.expired() {
+		tokenScopesMu.Unlock()
+		<-r.done
+		return r.scopes, r.err
+	}
+	if len(tokenScopes) >= maxTokenScopes {
+		evictTokenScopes()
+	}
+	r = &scopeResult{done: make(chan struct{})}
+	tokenScopes[key] = r
+	tokenScopesMu.Unlock()
+
+	r.scopes, r.err = probeScopes(token)
+	r.detectedAt = time.Now()
+	close(r.done)
+	return r.scopes, r.err
+}
+
+func probeScopes(token string) (Scopes, error) {
+	options() // initializes httpClient
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	scopes := Scopes{}
+	for _, p := range scopeProbes {
+		granted, err := probeScope(ctx, token, p)
+		if err != nil {
+			log.Warnf("detect token scopes err: %v", err)
+			return nil, err
+		}
+		scopes[p.scope] = granted
+	}
+	log.Infof("Token scopes: %s", strings.Join(scopes.List(), ", "))
+	return scopes, nil
+}
+
+// evictTokenScopes drops the expired entries of tokenScopes, or the oldest
+// finished one when none has expired. It is called with tokenScopesMu held.
+func evictTokenScopes() {
+	var oldest [sha256.Size]byte
+	var oldestAt time.Time
+	for key, r := range tokenScopes {
+		if r.expired() {
+			delete(tokenScopes, key)
+			continue
+		}
+		select {
+		case <-r.done:
+		default:
+			continue // still probing
+		}
+		if oldestAt.IsZero() || r.detectedAt.Before(oldestAt) {
+			oldest, oldestAt = key, r.detectedAt
+		}
+	}
+	if len(tokenScopes) >= maxTokenScopes && !oldestAt.IsZero() {
+		delete(tokenScopes, oldest)
+	}
+}
+
+func probeScope(ctx context.Context, token string, p scopeProbe) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, p.method, strings.TrimSuffix(flag.Host, "/")+"/api/v1"+p.path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "token "+token)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("probe %s err: %v", p.scope, err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		return false, fmt.Errorf("probe %s err: token is invalid", p.scope)
+	case resp.StatusCode == http.StatusForbidden && strings.Contains(strings.ToLower(string(data)), "scope"):
+		return false, nil
+	default:
+		return true, nil
+	}
+}
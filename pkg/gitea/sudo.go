@@ -0,0 +1,72 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gitea.com/gitea/gitea-mcp/pkg/flag"
+)
+
+type sudoKey struct{}
+
+// WithSudo attaches the identity of the user a request acts for, as sent by
+// an authenticating proxy, to ctx.
+func WithSudo(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, sudoKey{}, identity)
+}
+
+// SudoIdentity returns the identity attached with WithSudo, if any.
+func SudoIdentity(ctx context.Context) string {
+	identity, _ := ctx.Value(sudoKey{}).(string)
+	return identity
+}
+
+// SudoFromRequest returns the user identity header of an HTTP or SSE request.
+func SudoFromRequest(r *http.Request) string {
+	return strings.TrimSpace(r.Header.Get(flag.SudoHeader))
+}
+
+// Sudo returns the Gitea user requests made with ctx impersonate: the
+// request identity mapped through --sudo-map, or --sudo-user. It is empty
+// when impersonation is disabled.
+func Sudo(ctx context.Context) string {
+	if !flag.Sudo {
+		return ""
+	}
+	identity := SudoIdentity(ctx)
+	if identity == "" {
+		return flag.SudoUser
+	}
+	if user, ok := flag.SudoMap[identity]; ok {
+		return user
+	}
+	return identity
+}
+
+// SudoAllowed reports whether user is in the --sudo-allow list. Gitea user
+// names are case-insensitive.
+func SudoAllowed(user string) bool {
+	for _, allowed := range flag.SudoAllow {
+		if strings.EqualFold(allowed, user) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckSudo reports an impersonation setup that would reject every call.
+func CheckSudo() error {
+	if !flag.Sudo {
+		return nil
+	}
+	if len(flag.SudoAllow) == 0 {
+		return errors.New("--sudo requires --sudo-allow with the users that may be impersonated")
+	}
+	if flag.SudoUser != "" && !SudoAllowed(flag.SudoUser) {
+		return fmt.Errorf("--sudo-user %s is not in --sudo-allow", flag.SudoUser)
+	}
+	return nil
+}
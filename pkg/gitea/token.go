@@ -0,0 +1,45 @@
+package gitea
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"gitea.com/gitea/gitea-mcp/pkg/flag"
+)
+
+type tokenKey struct{}
+
+// WithToken attaches a per-user access token to ctx, used instead of the
+// configured token by ClientFromContext.
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, token)
+}
+
+// TokenFromContext returns the token attached with WithToken, if any.
+func TokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(tokenKey{}).(string)
+	return token
+}
+
+// Token returns the access token used for requests made with ctx.
+func Token(ctx context.Context) string {
+	if token := TokenFromContext(ctx); token != "" {
+		return token
+	}
+	return flag.Token
+}
+
+// TokenFromRequest extracts an access token from an "Authorization: Bearer"
+// or "Authorization: token" request header.
+func TokenFromRequest(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok {
+		return ""
+	}
+	switch strings.ToLower(scheme) {
+	case "bearer", "token":
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
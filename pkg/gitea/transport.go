@@ -0,0 +1,75 @@
+package gitea
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"gitea.com/gitea/gitea-mcp/pkg/flag"
+)
+
+// CheckTransport reports invalid TLS or proxy settings before any client is
+// created, since creating the clients treats them as fatal.
+func CheckTransport() error {
+	_, err := newTransport()
+	return err
+}
+
+// newTransport builds the transport used for Gitea requests from the TLS and
+// proxy flags. It starts from a copy of http.DefaultTransport so the settings
+// never leak into other HTTP clients of the process, such as the OTLP exporter.
+func newTransport() (*http.Transport, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: flag.Insecure,
+	}
+	if flag.CACert != "" {
+		pem, err := os.ReadFile(flag.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("read CA certificate err: %v", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates found in %s", flag.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if flag.ClientCert != "" || flag.ClientKey != "" {
+		if flag.ClientCert == "" || flag.ClientKey == "" {
+			return nil, errors.New("--client-cert and --client-key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(flag.ClientCert, flag.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate err: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	t.TLSClientConfig = tlsConfig
+
+	switch flag.Proxy {
+	case "":
+		// keep http.ProxyFromEnvironment
+	case "none":
+		t.Proxy = nil
+	default:
+		u, err := url.Parse(flag.Proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL: %s", flag.Proxy)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("invalid proxy scheme: %s. Must be http, https or socks5", u.Scheme)
+		}
+		t.Proxy = http.ProxyURL(u)
+	}
+	return t, nil
+}
@@ -0,0 +1,117 @@
+package gitea
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gitea.com/gitea/gitea-mcp/pkg/flag"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+	"gitea.com/gitea/gitea-mcp/pkg/tool"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/hashicorp/go-version"
+)
+
+// Feature is a tool that needs a minimum Gitea version, as declared with
+// tool.RequireVersion.
+type Feature struct {
+	Name       string `json:"name"`
+	MinVersion string `json:"min_version"`
+	Supported  bool   `json:"supported"`
+}
+
+// features lists the version gated tools reported by
+// get_gitea_mcp_server_version, sorted by name.
+func features() []Feature {
+	var features []Feature
+	for _, info := range tool.All() {
+		if info.MinVersion != "" {
+			features = append(features, Feature{Name: info.Tool.Name, MinVersion: info.MinVersion})
+		}
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i].Name < features[j].Name })
+	return features
+}
+
+// ServerInfo describes the connected Gitea or Forgejo server.
+type ServerInfo struct {
+	Host string `json:"host"`
+	// Flavor is "gitea" or "forgejo", empty when the server is unreachable.
+	Flavor  string `json:"flavor,omitempty"`
+	Version string `json:"version,omitempty"`
+	// GiteaVersion is the Gitea version the server's API is compatible with,
+	// used to gate tools. It equals Version on Gitea.
+	GiteaVersion string    `json:"gitea_version,omitempty"`
+	Features     []Feature `json:"features,omitempty"`
+	Error        string    `json:"error,omitempty"`
+}
+
+var (
+	serverInfo     ServerInfo
+	serverInfoOnce sync.Once
+)
+
+// Server detects the connected server once and returns its version and
+// supported features.
+func Server() ServerInfo {
+	serverInfoOnce.Do(func() {
+		serverInfo = ServerInfo{Host: flag.Host}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		c, err := gitea.NewClient(flag.Host, append(options(),
+			gitea.SetContext(ctx),
+			gitea.SetGiteaVersion(""),
+		)...)
+		if err != nil {
+			serverInfo.Error = err.Error()
+			return
+		}
+		raw, _, err := c.ServerVersion()
+		if err != nil {
+			log.Warnf("get gitea server version err: %v", err)
+			serverInfo.Error = err.Error()
+			return
+		}
+		serverInfo.Flavor, serverInfo.Version, serverInfo.GiteaVersion = parseServerVersion(raw)
+		for _, f := range features() {
+			f.Supported = versionAtLeast(serverInfo.GiteaVersion, f.MinVersion)
+			serverInfo.Features = append(serverInfo.Features, f)
+		}
+		log.Infof("Connected to %s %s", serverInfo.Flavor, serverInfo.Version)
+	})
+	return serverInfo
+}
+
+// parseServerVersion splits a /version response into flavor, version and the
+// Gitea-compatible version. Forgejo reports e.g. "7.0.5+gitea-1.21.11".
+func parseServerVersion(raw string) (flavor, v, giteaVersion string) {
+	raw = strings.TrimSpace(raw)
+	if _, compat, ok := strings.Cut(raw, "+gitea-"); ok {
+		return "forgejo", raw, compat
+	}
+	return "gitea", raw, raw
+}
+
+// SupportsVersion reports whether the connected server is at least
+// minVersion. It returns true when the server version is unknown, so tools
+// are not hidden because of a failed detection.
+func SupportsVersion(minVersion string) bool {
+	return versionAtLeast(Server().GiteaVersion, minVersion)
+}
+
+func versionAtLeast(v, minVersion string) bool {
+	current, err := version.NewVersion(v)
+	if err != nil {
+		return true
+	}
+	required, err := version.NewVersion(minVersion)
+	if err != nil {
+		return true
+	}
+	// compare without pre-release so 1.22.0+dev and 1.22.0-rc1 count as 1.22
+	return current.Core().GreaterThanOrEqual(required)
+}
@@ -3,6 +3,7 @@ package log
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -27,38 +28,39 @@ func Default() *zap.Logger {
 			var ws zapcore.WriteSyncer
 			var wss []zapcore.WriteSyncer
 
-			home, _ := os.UserHomeDir()
-			if home == "" {
-				home = os.TempDir()
+			maxSize := flag.LogMaxSize
+			if maxSize <= 0 {
+				maxSize = 100
 			}
-
-			logDir := fmt.Sprintf("%s/.gitea-mcp", home)
-			if err := os.MkdirAll(logDir, 0o700); err != nil {
-				// Fallback to temp directory if creation fails
-				logDir = os.TempDir()
-			}
-
 			wss = append(wss, zapcore.AddSync(&lumberjack.Logger{
-				Filename:   fmt.Sprintf("%s/gitea-mcp.log", logDir),
-				MaxSize:    100,
+				Filename:   logPath(),
+				MaxSize:    maxSize,
 				MaxBackups: 10,
 				MaxAge:     30,
 			}))
 
 			if flag.Mode == "http" || flag.Mode == "sse" {
 				wss = append(wss, zapcore.AddSync(os.Stdout))
+			} else if flag.LogStderr {
+				// stdout carries the MCP protocol in stdio mode
+				wss = append(wss, zapcore.AddSync(os.Stderr))
 			}
 
 			ws = zapcore.NewMultiWriteSyncer(wss...)
 
-			enc := zapcore.NewConsoleEncoder(ec)
-			var level zapcore.Level
-			if flag.Debug {
-				level = zapcore.DebugLevel
+			var enc zapcore.Encoder
+			if flag.LogFormat == "json" {
+				ec.EncodeLevel = zapcore.LowercaseLevelEncoder
+				ec.EncodeTime = zapcore.ISO8601TimeEncoder
+				enc = zapcore.NewJSONEncoder(ec)
 			} else {
-				level = zapcore.InfoLevel
+				enc = zapcore.NewConsoleEncoder(ec)
 			}
-			core := zapcore.NewCore(enc, ws, level)
+			AddSecret(flag.Token)
+			AddSensitiveArgs(flag.LogRedactArgs...)
+			enc = redactEncoder{enc}
+
+			core := zapcore.NewCore(enc, ws, logLevel())
 			options := []zap.Option{
 				zap.AddStacktrace(zapcore.DPanicLevel),
 				zap.AddCaller(),
@@ -71,6 +73,63 @@ func Default() *zap.Logger {
 	return defaultLogger
 }
 
+func logPath() string {
+	if flag.LogPath != "" {
+		if err := os.MkdirAll(filepath.Dir(flag.LogPath), 0o700); err == nil {
+			return flag.LogPath
+		}
+	}
+
+	home, _ := os.UserHomeDir()
+	if home == "" {
+		home = os.TempDir()
+	}
+
+	logDir := fmt.Sprintf("%s/.gitea-mcp", home)
+	if err := os.MkdirAll(logDir, 0o700); err != nil {
+		// Fallback to temp directory if creation fails
+		logDir = os.TempDir()
+	}
+	return fmt.Sprintf("%s/gitea-mcp.log", logDir)
+}
+
+// CheckFlags reports an invalid log level or format before the logger is
+// created, since the logger cannot report its own settings.
+func CheckFlags() error {
+	if _, err := parseLevel(); err != nil {
+		return err
+	}
+	switch flag.LogFormat {
+	case "", "console", "json":
+		return nil
+	default:
+		return fmt.Errorf("invalid log format %q, want console or json", flag.LogFormat)
+	}
+}
+
+func parseLevel() (zapcore.Level, error) {
+	if flag.LogLevel != "" {
+		level, err := zapcore.ParseLevel(flag.LogLevel)
+		if err != nil {
+			return level, fmt.Errorf("invalid log level %q, want debug, info, warn or error", flag.LogLevel)
+		}
+		return level, nil
+	}
+	if flag.Debug {
+		return zapcore.DebugLevel, nil
+	}
+	return zapcore.InfoLevel, nil
+}
+
+func logLevel() zapcore.Level {
+	level, err := parseLevel()
+	if err != nil {
+		// rejected by CheckFlags
+		return zapcore.InfoLevel
+	}
+	return level
+}
+
 func SetDefault(logger *zap.Logger) {
 	if logger != nil {
 		defaultLogger = logger
@@ -0,0 +1,109 @@
+package log
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+const redacted = "[REDACTED]"
+
+// defaultSensitiveArgs are argument and field names whose values are always
+// scrubbed from log lines.
+var defaultSensitiveArgs = []string{
+	"token",
+	"access_token",
+	"password",
+	"secret",
+	"client_secret",
+	"otp",
+}
+
+var (
+	authHeaderRe = regexp.MustCompile(`(?i)((?:authorization|proxy-authorization)\\?["']?\s*[:=]\s*\\?["']?\[?\s*)(token|bearer|basic)\s+[^\s"'\\\],]+`)
+
+	redactMu     sync.RWMutex
+	secrets      []string
+	sensitiveRes []*regexp.Regexp
+)
+
+func init() {
+	AddSensitiveArgs(defaultSensitiveArgs...)
+}
+
+// AddSecret registers a literal value, such as an access token, that must
+// never appear in log output.
+func AddSecret(secret string) {
+	if len(secret) < 4 {
+		return
+	}
+	redactMu.Lock()
+	defer redactMu.Unlock()
+	secrets = append(secrets, secret)
+}
+
+// AddSensitiveArgs registers argument names whose values are scrubbed from
+// log output, both in JSON ("name": "value") and key=value form.
+func AddSensitiveArgs(names ...string) {
+	redactMu.Lock()
+	defer redactMu.Unlock()
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		sensitiveRes = append(sensitiveRes, regexp.MustCompile(
+			`(?i)(\b`+regexp.QuoteMeta(name)+`\b\\?["']?\s*[:=]\s*)(\\"(?:[^\\]|\\[^"])*?\\"|"(?:[^"\\]|\\.)*"|[^\s,&}\]\\]+)`,
+		))
+	}
+}
+
+// Redact scrubs registered secrets, Authorization header values and
+// sensitive argument values from s.
+func Redact(s string) string {
+	redactMu.RLock()
+	defer redactMu.RUnlock()
+	for _, secret := range secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	s = authHeaderRe.ReplaceAllString(s, "${1}${2} "+redacted)
+	for _, re := range sensitiveRes {
+		s = re.ReplaceAllStringFunc(s, func(m string) string {
+			sub := re.FindStringSubmatch(m)
+			if strings.HasPrefix(sub[2], `\"`) {
+				return sub[1] + `\"` + redacted + `\"`
+			}
+			if strings.HasPrefix(sub[2], `"`) {
+				return sub[1] + `"` + redacted + `"`
+			}
+			return sub[1] + redacted
+		})
+	}
+	return s
+}
+
+// redactEncoder applies Redact to every encoded log line.
+type redactEncoder struct {
+	zapcore.Encoder
+}
+
+var bufferPool = buffer.NewPool()
+
+func (e redactEncoder) Clone() zapcore.Encoder {
+	return redactEncoder{e.Encoder.Clone()}
+}
+
+func (e redactEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	buf, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	line := buf.String()
+	buf.Free()
+	out := bufferPool.Get()
+	out.AppendString(Redact(line))
+	return out, nil
+}
@@ -3,6 +3,7 @@ package to
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"gitea.com/gitea/gitea-mcp/pkg/log"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -26,3 +27,37 @@ func ErrorResult(err error) (*mcp.CallToolResult, error) {
 	log.Errorf(err.Error())
 	return nil, err
 }
+
+// Strings converts an array argument, which JSON decodes to []any, to a
+// string slice, skipping empty and non-string items.
+func Strings(v any) []string {
+	var items []any
+	switch v := v.(type) {
+	case []string:
+		return v
+	case []any:
+		items = v
+	default:
+		return nil
+	}
+	strs := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+// Time parses a date argument given as RFC 3339 or as a plain date, which
+// means midnight UTC.
+func Time(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD or RFC 3339", s)
+	}
+	return t, nil
+}
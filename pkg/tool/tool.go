@@ -1,37 +1,240 @@
 package tool
 
 import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
 	"gitea.com/gitea/gitea-mcp/pkg/flag"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
+// Gitea access token scope categories.
+const (
+	ScopeRepository   = "repository"
+	ScopeIssue        = "issue"
+	ScopeUser         = "user"
+	ScopeOrganization = "organization"
+)
+
+// ReadScope returns the token scope needed to read resources of the category.
+func ReadScope(category string) string {
+	return "read:" + category
+}
+
+// WriteScope returns the token scope needed to write resources of the category.
+func WriteScope(category string) string {
+	return "write:" + category
+}
+
 type Tool struct {
-	write []server.ServerTool
-	read  []server.ServerTool
+	category    string
+	write       []server.ServerTool
+	read        []server.ServerTool
+	scopes      map[string][]string
+	minVersions map[string]string
 }
 
-func New() *Tool {
-	return &Tool{
-		write: make([]server.ServerTool, 100),
-		read:  make([]server.ServerTool, 100),
+// Info describes a registered tool regardless of the read-only setting.
+type Info struct {
+	Tool       mcp.Tool
+	Write      bool
+	Scopes     []string
+	MinVersion string
+}
+
+// Filter decides whether a registered tool is exposed. It returns false and
+// the reason when the tool has to be hidden.
+type Filter func(info Info) (ok bool, reason string)
+
+var (
+	registries []*Tool
+	filters    []func() Filter
+)
+
+// New creates a tool registry whose tools require the read or write token
+// scope of category unless registered with explicit scopes. An empty category
+// means the tools need no token scope.
+func New(category string) *Tool {
+	t := &Tool{
+		category:    category,
+		write:       make([]server.ServerTool, 0, 100),
+		read:        make([]server.ServerTool, 0, 100),
+		scopes:      make(map[string][]string),
+		minVersions: make(map[string]string),
 	}
+	registries = append(registries, t)
+	return t
 }
 
-func (t *Tool) RegisterWrite(s server.ServerTool) {
+// AddFilter registers a filter applied by Tools to every registry. newFilter
+// is called once per pass over the tools, so a filter can look up what it
+// needs, such as the token scopes, once rather than for every tool.
+func AddFilter(newFilter func() Filter) {
+	filters = append(filters, newFilter)
+}
+
+// RegisterWrite registers a write tool. scopes overrides the default
+// write scope of the registry's category.
+func (t *Tool) RegisterWrite(s server.ServerTool, scopes ...string) {
+	if len(scopes) == 0 && t.category != "" {
+		scopes = []string{WriteScope(t.category)}
+	}
+	t.scopes[s.Tool.Name] = scopes
 	t.write = append(t.write, s)
 }
 
-func (t *Tool) RegisterRead(s server.ServerTool) {
+// RegisterRead registers a read tool. scopes overrides the default
+// read scope of the registry's category.
+func (t *Tool) RegisterRead(s server.ServerTool, scopes ...string) {
+	if len(scopes) == 0 && t.category != "" {
+		scopes = []string{ReadScope(t.category)}
+	}
+	t.scopes[s.Tool.Name] = scopes
 	t.read = append(t.read, s)
 }
 
+// RequireVersion marks the named tool as needing at least the given Gitea
+// version, e.g. "1.15.0".
+func (t *Tool) RequireVersion(name, version string) {
+	t.minVersions[name] = version
+}
+
+// Scopes returns the token scopes required by the named tool.
+func (t *Tool) Scopes(name string) []string {
+	return t.scopes[name]
+}
+
+// MinVersion returns the minimum Gitea version of the named tool, if any.
+func (t *Tool) MinVersion(name string) string {
+	return t.minVersions[name]
+}
+
 func (t *Tool) Tools() []server.ServerTool {
+	active := activeFilters()
 	tools := make([]server.ServerTool, 0, len(t.write)+len(t.read))
-	if flag.ReadOnly {
-		tools = append(tools, t.read...)
-		return tools
+	if !flag.ReadOnly {
+		for _, s := range t.write {
+			tools = t.appendTool(tools, active, s, true)
+		}
+	}
+	for _, s := range t.read {
+		tools = t.appendTool(tools, active, s, false)
 	}
-	tools = append(tools, t.write...)
-	tools = append(tools, t.read...)
 	return tools
 }
+
+func (t *Tool) appendTool(tools []server.ServerTool, active []Filter, s server.ServerTool, write bool) []server.ServerTool {
+	info := t.info(s.Tool, write)
+	if ok, _ := apply(active, info); !ok {
+		return tools
+	}
+	return append(tools, withVersionHint(s, info))
+}
+
+// Infos returns every registered tool, including write tools in read-only mode.
+func (t *Tool) Infos() []Info {
+	infos := make([]Info, 0, len(t.write)+len(t.read))
+	for _, s := range t.write {
+		infos = append(infos, t.info(s.Tool, true))
+	}
+	for _, s := range t.read {
+		infos = append(infos, t.info(s.Tool, false))
+	}
+	return infos
+}
+
+func (t *Tool) info(tool mcp.Tool, write bool) Info {
+	return Info{
+		Tool:       tool,
+		Write:      write,
+		Scopes:     t.Scopes(tool.Name),
+		MinVersion: t.MinVersion(tool.Name),
+	}
+}
+
+// withVersionHint points at the version requirement when a tool fails after
+// a Gitea request answered 404 Not Found, which is how older servers answer
+// unknown endpoints.
+func withVersionHint(s server.ServerTool, info Info) server.ServerTool {
+	if info.MinVersion == "" {
+		return s
+	}
+	handler := s.Handler
+	s.Handler = func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		notFound := new(atomic.Bool)
+		result, err := handler(context.WithValue(ctx, notFoundKey{}, notFound), req)
+		if err != nil && notFound.Load() {
+			return result, fmt.Errorf("%v (%s requires Gitea >= %s)", err, info.Tool.Name, info.MinVersion)
+		}
+		return result, err
+	}
+	return s
+}
+
+type notFoundKey struct{}
+
+// RecordNotFound notes that a Gitea request made with ctx was answered with
+// 404 Not Found, for the version hint of the tool call ctx belongs to.
+func RecordNotFound(ctx context.Context) {
+	if notFound, ok := ctx.Value(notFoundKey{}).(*atomic.Bool); ok {
+		notFound.Store(true)
+	}
+}
+
+// activeFilters creates the filters for one pass over the tools.
+func activeFilters() []Filter {
+	active := make([]Filter, 0, len(filters))
+	for _, newFilter := range filters {
+		active = append(active, newFilter())
+	}
+	return active
+}
+
+func apply(active []Filter, info Info) (bool, string) {
+	for _, f := range active {
+		if ok, reason := f(info); !ok {
+			return false, reason
+		}
+	}
+	return true, ""
+}
+
+// All returns every tool of every registry.
+func All() []Info {
+	var infos []Info
+	for _, t := range registries {
+		infos = append(infos, t.Infos()...)
+	}
+	return infos
+}
+
+// Hidden returns the tools that are registered but not exposed, keyed by
+// tool name, with the reason they are hidden.
+func Hidden() map[string]string {
+	active := activeFilters()
+	hidden := make(map[string]string)
+	for _, info := range All() {
+		if info.Write && flag.ReadOnly {
+			hidden[info.Tool.Name] = "write tool disabled in read-only mode"
+			continue
+		}
+		if ok, reason := apply(active, info); !ok {
+			hidden[info.Tool.Name] = reason
+		}
+	}
+	return hidden
+}
+
+// NumberOrString makes a property accept a number or a string, for
+// arguments such as a milestone that are given by ID or by title.
+func NumberOrString() mcp.PropertyOption {
+	return func(schema map[string]any) {
+		delete(schema, "type")
+		schema["oneOf"] = []any{
+			map[string]any{"type": "number"},
+			map[string]any{"type": "string"},
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"gitea.com/gitea/gitea-mcp/pkg/flag"
+	"gitea.com/gitea/gitea-mcp/pkg/log"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "gitea.com/gitea/gitea-mcp"
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Enabled reports whether an OTLP endpoint is configured by flag or by the
+// standard OTEL_EXPORTER_OTLP_* environment variables.
+func Enabled() bool {
+	return flag.OTLPEndpoint != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Init installs the global propagator and, when tracing is enabled, an OTLP
+// tracer provider. The returned function flushes and stops the exporter.
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if flag.OTLPEndpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(flag.OTLPEndpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp exporter err: %v", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			semconv.ServiceName("gitea-mcp"),
+			semconv.ServiceVersion(flag.Version),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create otel resource err: %v", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	log.Infof("OpenTelemetry tracing enabled")
+	return tp.Shutdown, nil
+}
+
+// ContextFromRequest extracts the incoming trace context from HTTP/SSE request
+// headers so tool call spans join the caller's trace.
+func ContextFromRequest(ctx context.Context, r *http.Request) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+}
+
+// ToolMiddleware records each MCP tool call as a span.
+func ToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		attrs := []attribute.KeyValue{
+			attribute.String("mcp.tool.name", req.Params.Name),
+		}
+		if owner, ok := req.GetArguments()["owner"].(string); ok {
+			attrs = append(attrs, attribute.String("gitea.owner", owner))
+		}
+		if repo, ok := req.GetArguments()["repo"].(string); ok {
+			attrs = append(attrs, attribute.String("gitea.repo", repo))
+		}
+		ctx, span := tracer().Start(ctx, "tools/call "+req.Params.Name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attrs...),
+		)
+		defer span.End()
+
+		result, err := next(ctx, req)
+		switch {
+		case err != nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			span.SetAttributes(attribute.String("mcp.tool.outcome", "error"))
+		case result != nil && result.IsError:
+			span.SetStatus(codes.Error, "tool returned an error result")
+			span.SetAttributes(attribute.String("mcp.tool.outcome", "error"))
+		default:
+			span.SetAttributes(attribute.String("mcp.tool.outcome", "ok"))
+		}
+		return result, err
+	}
+}
+
+// Transport wraps next so every outbound Gitea API request is a client span
+// and carries the trace context.
+func Transport(next http.RoundTripper) http.RoundTripper {
+	return &transport{next: next}
+}
+
+type transport struct {
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := route(req.URL.Path)
+	ctx, span := tracer().Start(req.Context(), req.Method+" "+r,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(req.Method),
+			semconv.HTTPRoute(r),
+			semconv.ServerAddress(req.URL.Hostname()),
+		),
+	)
+	defer span.End()
+
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
+
+var numericSegment = regexp.MustCompile(`^\d+$`)
+
+// route replaces owner, repository, user, ref, file path and numeric path
+// segments with placeholders to keep span names low-cardinality, e.g.
+// /api/v1/repos/gitea/tea/issues/12 -> /api/v1/repos/{owner}/{repo}/issues/{id}.
+func route(path string) string {
+	segs := strings.Split(path, "/")
+	for i := 0; i < len(segs); i++ {
+		switch segs[i] {
+		case "repos":
+			if i+2 < len(segs) && segs[i+1] != "search" && segs[i+1] != "issues" {
+				segs[i+1], segs[i+2] = "{owner}", "{repo}"
+				i += 2
+			}
+			continue
+		case "contents", "raw", "media":
+			if i+1 < len(segs) && i > 0 && segs[i-1] == "{repo}" {
+				segs = append(segs[:i+1], "{filepath}")
+			}
+			continue
+		case "branches", "tags":
+			if i+1 < len(segs) && i > 0 && segs[i-1] == "{repo}" {
+				segs[i+1] = "{name}"
+				i++
+			}
+			continue
+		case "users", "orgs":
+			if i+1 < len(segs) && segs[i+1] != "search" {
+				segs[i+1] = "{" + strings.TrimSuffix(segs[i], "s") + "}"
+				i++
+			}
+			continue
+		}
+		if numericSegment.MatchString(segs[i]) {
+			segs[i] = "{id}"
+		}
+	}
+	return strings.Join(segs, "/")
+}